@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"time"
+	_ "time/tzdata"
 
 	"post-analyzer/config"
+	"post-analyzer/internal/adapters/feed"
 	"post-analyzer/internal/adapters/openrouter"
 	"post-analyzer/internal/adapters/telegram/bot"
 	"post-analyzer/internal/adapters/telegram/user"
@@ -44,8 +46,19 @@ func main() {
 		log.Fatalf("Failed to create databse tables: %v", err)
 	}
 
-	// repository
+	// repositories
 	repo := repository.NewSubscriptionRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	bundleRepo := repository.NewBundleRepository(db)
+	channelRepo := repository.NewChannelRepository(db)
+	sentRepo := repository.NewSentPostRepository(db)
+	feedEntryRepo := repository.NewFeedEntryRepository(db)
+
+	// posts are stored only in real-time ingestion mode
+	var postStore user.PostStore
+	if cfg.Ingestion.Realtime {
+		postStore = repository.NewPostRepository(db)
+	}
 
 	// bot registartion
 	botHandler, err := tgbot.New(cfg.API.Telegram.BotToken)
@@ -55,10 +68,31 @@ func main() {
 
 	// telegram clients
 	botClient := bot.NewTelegramBotClient(botHandler)
-	userClient, err := user.NewTelegramUserClient(cfg.API.Telegram.AppID, cfg.API.Telegram.AppHash, cfg.API.Telegram.SessionPath)
+	historyLimits := user.HistoryLimits{
+		PageSize: cfg.History.PageSize,
+		MaxPosts: cfg.History.MaxPosts,
+		MaxAge:   cfg.History.MaxAge,
+	}
+	rateLimits := user.RateLimits{
+		RequestsPerSecond: cfg.RateLimit.RequestsPerSecond,
+		MaxFloodWait:      cfg.RateLimit.MaxFloodWait,
+		MaxRetries:        cfg.RateLimit.MaxRetries,
+	}
+	authHandler := controllers.NewAuthController(botClient, settingsRepo, cfg.API.Telegram.AdminChatID)
+	authOpts := user.AuthOptions{
+		Method:   cfg.Auth.Method,
+		Phone:    cfg.API.Telegram.Phone,
+		Password: cfg.API.Telegram.Password,
+		Prompter: authHandler,
+	}
+	userClient, err := user.NewTelegramUserClient(cfg.API.Telegram.AppID, cfg.API.Telegram.AppHash, cfg.API.Telegram.SessionPath, authOpts, historyLimits, rateLimits, channelRepo, postStore)
 	if err != nil {
 		log.Fatalf("Failed to create user client: %v", err)
 	}
+	defer userClient.Stop()
+
+	// RSS/Atom feeds client
+	feedClient := feed.NewFeedClient(feed.NewHTTPClient(30*time.Second), channelRepo, feedEntryRepo, cfg.History.MaxPosts)
 
 	// OpenRouter client
 	aiClient := openrouter.NewOpenRouterClient(cfg.API.OpenRouter.APIKey)
@@ -70,7 +104,15 @@ func main() {
 	notifier := notifier.NewNotifier(botClient)
 
 	// usecase manager
-	ucManager := usecase.NewUseCaseManager(userClient, repo, scheduler, aiClient, notifier)
+	ucManager := usecase.NewUseCaseManager(userClient, feedClient, repo, settingsRepo, bundleRepo, sentRepo, scheduler, aiClient, notifier, cfg.Analysis.Models)
+
+	// restoring scheduled digests
+	restoreCtx, cancelRestore := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelRestore()
+
+	if err = ucManager.RestoreSchedules(restoreCtx); err != nil {
+		log.Fatalf("Failed to restore scheduled digests: %v", err)
+	}
 
 	// bot messages handler
 	handler := controllers.NewBotController(ucManager)
@@ -78,6 +120,29 @@ func main() {
 	// bot commands registration
 	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/start", tgbot.MatchTypeExact, handler.StartHandler)
 	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/monitor", tgbot.MatchTypePrefix, handler.MonitorHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/unsubscribe", tgbot.MatchTypePrefix, handler.UnsubscribeHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/edit", tgbot.MatchTypePrefix, handler.EditHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/digest", tgbot.MatchTypePrefix, handler.DigestHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/list", tgbot.MatchTypeExact, handler.ListHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/timezone", tgbot.MatchTypePrefix, handler.TimezoneHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/language", tgbot.MatchTypePrefix, handler.LanguageHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/bundles", tgbot.MatchTypeExact, handler.BundlesHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/bundle", tgbot.MatchTypePrefix, handler.BundleHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/unbundle", tgbot.MatchTypePrefix, handler.UnbundleHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/pause", tgbot.MatchTypePrefix, handler.PauseHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/resume", tgbot.MatchTypePrefix, handler.ResumeHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/options", tgbot.MatchTypePrefix, handler.OptionsHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/model", tgbot.MatchTypePrefix, handler.ModelHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeMessageText, "/prompt", tgbot.MatchTypePrefix, handler.PromptHandler)
+
+	// bot callbacks registration
+	botHandler.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "list:", tgbot.MatchTypePrefix, handler.ListPageHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "monitor:", tgbot.MatchTypePrefix, handler.WizardTimeHandler)
+	botHandler.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "resume:", tgbot.MatchTypePrefix, handler.ResumeModeHandler)
+
+	// conversation steps registration
+	botHandler.RegisterHandlerMatchFunc(authHandler.AuthCodeMatch, authHandler.AuthCodeHandler)
+	botHandler.RegisterHandlerMatchFunc(handler.WizardMatch, handler.WizardChannelHandler)
 
 	scheduler.Start()
 	botHandler.Start(context.Background())
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+
+	"post-analyzer/internal/adapters/telegram/user"
 
 	"gopkg.in/yaml.v3"
 )
@@ -23,6 +26,30 @@ type AppConfig struct {
 		SSLMode  string `yaml:"ssl_mode"`
 	} `yaml:"database"`
 
+	History struct {
+		PageSize int           `yaml:"page_size"`
+		MaxPosts int           `yaml:"max_posts"`
+		MaxAge   time.Duration `yaml:"max_age"`
+	} `yaml:"history"`
+
+	RateLimit struct {
+		RequestsPerSecond float64       `yaml:"requests_per_second"`
+		MaxFloodWait      time.Duration `yaml:"max_flood_wait"`
+		MaxRetries        int           `yaml:"max_retries"`
+	} `yaml:"rate_limit"`
+
+	Ingestion struct {
+		Realtime bool `yaml:"realtime"`
+	} `yaml:"ingestion"`
+
+	Auth struct {
+		Method string `yaml:"method"`
+	} `yaml:"auth"`
+
+	Analysis struct {
+		Models []string `yaml:"models"`
+	} `yaml:"analysis"`
+
 	API struct {
 		Telegram struct {
 			BotToken    string `yaml:"-"`
@@ -30,8 +57,9 @@ type AppConfig struct {
 			AppHash     string `yaml:"-"`
 			SessionPath string `yaml:"-"`
 
-			Phone    string `yaml:"-"`
-			Password string `yaml:"-"`
+			Phone       string `yaml:"-"`
+			Password    string `yaml:"-"`
+			AdminChatID int64  `yaml:"-"`
 		} `yaml:"-"`
 		OpenRouter struct {
 			APIKey string `yaml:"-"`
@@ -60,6 +88,10 @@ func LoadConfig(path string) (*AppConfig, error) {
 	cfg.API.Telegram.SessionPath = os.Getenv("SESSION_PATH")
 	cfg.API.Telegram.Phone = os.Getenv("AUTH_PHONE")
 	cfg.API.Telegram.Password = os.Getenv("AUTH_PASSWORD")
+	cfg.API.Telegram.AdminChatID, err = strconv.ParseInt(os.Getenv("ADMIN_CHAT_ID"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("ADMIN_CHAT_ID не задан в переменных окружения")
+	}
 	cfg.API.OpenRouter.APIKey = os.Getenv("OPENROUTER_API_KEY")
 
 	if cfg.Database.Password == "" {
@@ -77,12 +109,9 @@ func LoadConfig(path string) (*AppConfig, error) {
 	if cfg.API.Telegram.SessionPath == "" {
 		return nil, fmt.Errorf("SESSION_PATH не задан в переменных окружения")
 	}
-	if cfg.API.Telegram.Phone == "" {
+	if cfg.API.Telegram.Phone == "" && cfg.Auth.Method != user.AuthMethodQR {
 		return nil, fmt.Errorf("AUTH_PHONE не задан в переменных окружения")
 	}
-	if cfg.API.Telegram.Password == "" {
-		return nil, fmt.Errorf("AUTH_PASSWORD не задан в переменных окружения")
-	}
 	if cfg.API.OpenRouter.APIKey == "" {
 		return nil, fmt.Errorf("OPENROUTER_API_KEY не задан в переменных окружения")
 	}
@@ -0,0 +1,293 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"post-analyzer/internal/adapters/source"
+	"post-analyzer/internal/domain/entity"
+)
+
+var (
+	ErrFeedUnavailable = errors.New("feed unavailable")
+
+	ErrFeedFormat = errors.New("unsupported feed format")
+
+	ErrFeedTooLarge = fmt.Errorf("%w: feed too large", ErrFeedUnavailable)
+
+	ErrChannelNotFound = errors.New("no feed found")
+
+	ErrNoPosts = errors.New("no posts found")
+
+	ErrTimeLimit = errors.New("feed req time limit reached")
+)
+
+const (
+	maxFeedSize     = 10 << 20
+	defaultMaxPosts = 500
+
+	userAgent = "post-analyzer/1.0 (+feed reader)"
+)
+
+type FeedService interface {
+	source.Source
+	ResolveFeed(ctx context.Context, feedURL string) (*entity.Channel, error)
+}
+
+// ChannelStore maps feed channel IDs back to their URLs.
+type ChannelStore interface {
+	GetChannel(ctx context.Context, channelID int64) (*entity.Channel, error)
+	SaveChannel(ctx context.Context, channel *entity.Channel) error
+}
+
+// EntryStore remembers when feed entries were first seen and returns the
+// first-seen times of the given entries.
+type EntryStore interface {
+	SeeEntries(ctx context.Context, channelID int64, entryIDs []int64, seenAt time.Time) (map[int64]time.Time, error)
+}
+
+type feedClient struct {
+	httpClient *http.Client
+	store      ChannelStore
+	entries    EntryStore
+	maxPosts   int
+}
+
+// NewFeedClient reads RSS and Atom feeds with the given HTTP client, feeds
+// are fetched whole on every call. Entries are read in the order they were
+// first seen, which entries stores.
+func NewFeedClient(httpClient *http.Client, store ChannelStore, entries EntryStore, maxPosts int) *feedClient {
+
+	if maxPosts <= 0 {
+		maxPosts = defaultMaxPosts
+	}
+
+	return &feedClient{
+		httpClient: httpClient,
+		store:      store,
+		entries:    entries,
+		maxPosts:   maxPosts,
+	}
+}
+
+// ResolveFeed checks that the URL serves a feed and saves it as a channel.
+func (c *feedClient) ResolveFeed(ctx context.Context, feedURL string) (*entity.Channel, error) {
+
+	channel := &entity.Channel{
+		ID:      entity.FeedChannelID(feedURL),
+		FeedURL: feedURL,
+	}
+
+	title, _, err := c.fetch(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	channel.Title = title
+	if channel.Title == "" {
+		if parsed, err := url.Parse(feedURL); err == nil {
+			channel.Title = parsed.Host
+		}
+	}
+
+	if err := c.store.SaveChannel(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// ChannelPosts returns entries first seen after the read position
+// lastReadID. Feeds date entries loosely (several per second, date-only
+// days, backdating), so the position is the first-seen time of the newest
+// entry read rather than any entry ID or date.
+func (c *feedClient) ChannelPosts(ctx context.Context, channelID int64, lastReadID int64) (*entity.ChannelHistory, error) {
+
+	posts, err := c.posts(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen, err := c.see(ctx, channelID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &entity.ChannelHistory{
+		Fetched: len(posts),
+	}
+
+	for _, post := range posts {
+
+		position := seen[post.ID].UnixMicro()
+		history.Position = max(history.Position, position)
+
+		if position > lastReadID {
+			history.Posts = append(history.Posts, post)
+		}
+	}
+
+	if len(history.Posts) > c.maxPosts {
+		history.Limited = true
+		history.Truncated = len(history.Posts) - c.maxPosts
+		history.Posts = history.Posts[:c.maxPosts]
+	}
+
+	return history, nil
+}
+
+func (c *feedClient) ChannelRecentPosts(ctx context.Context, channelID int64, since time.Time) (*entity.ChannelHistory, error) {
+
+	posts, err := c.posts(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &entity.ChannelHistory{
+		Fetched: len(posts),
+	}
+
+	for _, post := range posts {
+		if !post.Date.Before(since) {
+			history.Posts = append(history.Posts, post)
+		}
+	}
+
+	if len(history.Posts) == 0 {
+		return nil, ErrNoPosts
+	}
+
+	if len(history.Posts) > c.maxPosts {
+		history.Limited = true
+		history.Truncated = len(history.Posts) - c.maxPosts
+		history.Posts = history.Posts[:c.maxPosts]
+	}
+
+	return history, nil
+}
+
+func (c *feedClient) LatestPostID(ctx context.Context, channelID int64) (int64, error) {
+
+	posts, err := c.posts(ctx, channelID)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(posts) == 0 {
+		return 0, ErrNoPosts
+	}
+
+	seen, err := c.see(ctx, channelID, posts)
+	if err != nil {
+		return 0, err
+	}
+
+	var position int64
+	for _, firstSeen := range seen {
+		position = max(position, firstSeen.UnixMicro())
+	}
+
+	return position, nil
+}
+
+// Posts is not supported: feeds drop old entries on their own, so a missing
+// entry does not mean it was deleted.
+func (c *feedClient) Posts(ctx context.Context, channelID int64, postIDs []int64) (map[int64]*entity.Post, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Comments is not supported, feeds carry no discussions.
+func (c *feedClient) Comments(ctx context.Context, channelID int64, postIDs []int64, limit int) (map[int64][]*entity.Post, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (c *feedClient) ChannelDetails(ctx context.Context, channelID int64) (*entity.Channel, error) {
+
+	channel, err := c.store.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNotFound, err)
+	}
+
+	return channel, nil
+}
+
+// Watch does nothing, feeds are always read on demand.
+func (c *feedClient) Watch(channelID int64) {}
+
+// Unwatch does nothing, feeds are always read on demand.
+func (c *feedClient) Unwatch(channelID int64) {}
+
+func (c *feedClient) posts(ctx context.Context, channelID int64) ([]*entity.Post, error) {
+
+	channel, err := c.store.GetChannel(ctx, channelID)
+	if err != nil || channel.FeedURL == "" {
+		return nil, fmt.Errorf("%w: %d", ErrChannelNotFound, channelID)
+	}
+
+	_, posts, err := c.fetch(ctx, channel)
+	return posts, err
+}
+
+// see records the entries currently in the feed and returns when each of
+// them was first seen.
+func (c *feedClient) see(ctx context.Context, channelID int64, posts []*entity.Post) (map[int64]time.Time, error) {
+
+	ids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	seen, err := c.entries.SeeEntries(ctx, channelID, ids, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFeedUnavailable, err)
+	}
+
+	return seen, nil
+}
+
+func (c *feedClient) fetch(ctx context.Context, channel *entity.Channel) (string, []*entity.Post, error) {
+
+	if ctx.Err() != nil {
+		return "", nil, ErrTimeLimit
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, channel.FeedURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrFeedUnavailable, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml;q=0.9, */*;q=0.8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", nil, ErrTimeLimit
+		}
+		return "", nil, fmt.Errorf("%w: %s", ErrFeedUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("%w: status %d", ErrFeedUnavailable, resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxFeedSize {
+		return "", nil, fmt.Errorf("%w: %d bytes", ErrFeedTooLarge, resp.ContentLength)
+	}
+
+	// one byte over the limit tells a cut off feed from one that fits exactly
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrFeedUnavailable, err)
+	}
+	if len(data) > maxFeedSize {
+		return "", nil, fmt.Errorf("%w: over %d bytes", ErrFeedTooLarge, maxFeedSize)
+	}
+
+	return parseFeed(channel.ID, data)
+}
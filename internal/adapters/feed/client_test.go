@@ -0,0 +1,320 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+	<title>Example News</title>
+	<item>
+		<guid>https://example.com/1</guid>
+		<title>First</title>
+		<link>https://example.com/1</link>
+		<description>&lt;p&gt;Older &lt;b&gt;entry&lt;/b&gt;&lt;/p&gt;</description>
+		<pubDate>Mon, 02 Jun 2025 09:00:00 +0000</pubDate>
+	</item>
+	<item>
+		<guid>https://example.com/2</guid>
+		<title>Second</title>
+		<link>https://example.com/2</link>
+		<description>Newer entry</description>
+		<pubDate>Mon, 02 Jun 2025 10:00:00 +0000</pubDate>
+	</item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example Blog</title>
+	<entry>
+		<id>tag:example.com,2025:1</id>
+		<title>Hello</title>
+		<link rel="alternate" href="https://example.com/hello"/>
+		<summary>Atom summary</summary>
+		<updated>2025-06-02T09:00:00Z</updated>
+	</entry>
+</feed>`
+
+// rssWindows1251 says "Привет" and "Мир" in windows-1251.
+var rssWindows1251 = "<?xml version=\"1.0\" encoding=\"windows-1251\"?>\n" +
+	"<rss version=\"2.0\"><channel><title>\xcf\xf0\xe8\xe2\xe5\xf2</title>" +
+	"<item><title>\xcc\xe8\xf0</title><link>https://example.ru/1</link>" +
+	"<pubDate>Mon, 02 Jun 2025 09:00:00 +0300</pubDate></item>" +
+	"</channel></rss>"
+
+// feedServer serves a feed body that tests can replace between reads.
+type feedServer struct {
+	mu     sync.Mutex
+	status int
+	body   string
+}
+
+func (s *feedServer) set(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, body
+}
+
+func (s *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.WriteHeader(s.status)
+	w.Write([]byte(s.body))
+}
+
+type channelStore struct {
+	mu       sync.Mutex
+	channels map[int64]*entity.Channel
+}
+
+func (s *channelStore) GetChannel(ctx context.Context, channelID int64) (*entity.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channel, ok := s.channels[channelID]
+	if !ok {
+		return nil, errors.New("channel not found")
+	}
+	return channel, nil
+}
+
+func (s *channelStore) SaveChannel(ctx context.Context, channel *entity.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[channel.ID] = channel
+	return nil
+}
+
+// entryStore advances its own clock by a second on every call, so entries
+// seen on different reads never share a first-seen time.
+type entryStore struct {
+	mu    sync.Mutex
+	clock time.Time
+	seen  map[int64]time.Time
+}
+
+func (s *entryStore) SeeEntries(ctx context.Context, channelID int64, entryIDs []int64, seenAt time.Time) (map[int64]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = s.clock.Add(time.Second)
+
+	result := make(map[int64]time.Time, len(entryIDs))
+	for _, id := range entryIDs {
+		if _, ok := s.seen[id]; !ok {
+			s.seen[id] = s.clock
+		}
+		result[id] = s.seen[id]
+	}
+	return result, nil
+}
+
+func newTestClient(t *testing.T, status int, body string, maxPosts int) (*feedClient, *feedServer, string) {
+
+	t.Helper()
+
+	server := &feedServer{status: status, body: body}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	store := &channelStore{channels: map[int64]*entity.Channel{}}
+	entries := &entryStore{
+		clock: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		seen:  map[int64]time.Time{},
+	}
+
+	return NewFeedClient(srv.Client(), store, entries, maxPosts), server, srv.URL
+}
+
+func TestResolveFeed(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		body  string
+		title string
+		texts []string
+	}{
+		{
+			name:  "rss",
+			body:  rssFeed,
+			title: "Example News",
+			texts: []string{"Second\nNewer entry", "First\nOlder entry"},
+		},
+		{
+			name:  "atom",
+			body:  atomFeed,
+			title: "Example Blog",
+			texts: []string{"Hello\nAtom summary"},
+		},
+		{
+			name:  "windows-1251",
+			body:  rssWindows1251,
+			title: "Привет",
+			texts: []string{"Мир"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			client, _, url := newTestClient(t, http.StatusOK, tt.body, 0)
+			ctx := context.Background()
+
+			channel, err := client.ResolveFeed(ctx, url)
+			if err != nil {
+				t.Fatalf("ResolveFeed() error = %v", err)
+			}
+			if channel.Title != tt.title {
+				t.Errorf("title = %q, want %q", channel.Title, tt.title)
+			}
+
+			history, err := client.ChannelPosts(ctx, channel.ID, -1)
+			if err != nil {
+				t.Fatalf("ChannelPosts() error = %v", err)
+			}
+			if len(history.Posts) != len(tt.texts) {
+				t.Fatalf("got %d posts, want %d", len(history.Posts), len(tt.texts))
+			}
+			for i, post := range history.Posts {
+				if post.Text != tt.texts[i] {
+					t.Errorf("post %d text = %q, want %q", i, post.Text, tt.texts[i])
+				}
+				if post.ChannelID != channel.ID {
+					t.Errorf("post %d channel = %d, want %d", i, post.ChannelID, channel.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveFeedErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"server error", http.StatusInternalServerError, rssFeed, ErrFeedUnavailable},
+		{"not found", http.StatusNotFound, "", ErrFeedUnavailable},
+		{"html page", http.StatusOK, "<html><body>Not a feed</body></html>", ErrFeedFormat},
+		{"not xml", http.StatusOK, "plain text", ErrFeedFormat},
+		{"too large", http.StatusOK, strings.Repeat(" ", maxFeedSize+1), ErrFeedTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			client, _, url := newTestClient(t, tt.status, tt.body, 0)
+
+			_, err := client.ResolveFeed(context.Background(), url)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ResolveFeed() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFeedClosedServer(t *testing.T) {
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	client := NewFeedClient(http.DefaultClient, &channelStore{channels: map[int64]*entity.Channel{}}, &entryStore{seen: map[int64]time.Time{}}, 0)
+
+	_, err := client.ResolveFeed(context.Background(), url)
+	if !errors.Is(err, ErrFeedUnavailable) {
+		t.Errorf("ResolveFeed() error = %v, want %v", err, ErrFeedUnavailable)
+	}
+}
+
+func TestChannelPostsReadPosition(t *testing.T) {
+
+	client, server, url := newTestClient(t, http.StatusOK, rssFeed, 0)
+	ctx := context.Background()
+
+	channel, err := client.ResolveFeed(ctx, url)
+	if err != nil {
+		t.Fatalf("ResolveFeed() error = %v", err)
+	}
+
+	first, err := client.ChannelPosts(ctx, channel.ID, -1)
+	if err != nil {
+		t.Fatalf("ChannelPosts() error = %v", err)
+	}
+	if len(first.Posts) != 2 {
+		t.Fatalf("first read: got %d posts, want 2", len(first.Posts))
+	}
+
+	position := first.NextReadID()
+	again, err := client.ChannelPosts(ctx, channel.ID, position)
+	if err != nil {
+		t.Fatalf("ChannelPosts() error = %v", err)
+	}
+	if len(again.Posts) != 0 {
+		t.Errorf("repeated read: got %d posts, want 0", len(again.Posts))
+	}
+
+	// a new entry dated like an old one is still new
+	server.set(http.StatusOK, strings.Replace(rssFeed, "<item>", `<item>
+		<guid>https://example.com/3</guid>
+		<title>Third</title>
+		<link>https://example.com/3</link>
+		<pubDate>Mon, 02 Jun 2025 09:00:00 +0000</pubDate>
+	</item>
+	<item>`, 1))
+
+	next, err := client.ChannelPosts(ctx, channel.ID, position)
+	if err != nil {
+		t.Fatalf("ChannelPosts() error = %v", err)
+	}
+	if len(next.Posts) != 1 || next.Posts[0].Text != "Third" {
+		t.Fatalf("read after update: got %v, want only the third entry", next.Posts)
+	}
+	if next.NextReadID() <= position {
+		t.Errorf("position did not advance: %d <= %d", next.NextReadID(), position)
+	}
+}
+
+func TestChannelPostsTruncated(t *testing.T) {
+
+	client, _, url := newTestClient(t, http.StatusOK, rssFeed, 1)
+	ctx := context.Background()
+
+	channel, err := client.ResolveFeed(ctx, url)
+	if err != nil {
+		t.Fatalf("ResolveFeed() error = %v", err)
+	}
+
+	history, err := client.ChannelPosts(ctx, channel.ID, -1)
+	if err != nil {
+		t.Fatalf("ChannelPosts() error = %v", err)
+	}
+	if len(history.Posts) != 1 || !history.Limited || history.Truncated != 1 {
+		t.Errorf("got %d posts with %d truncated, want 1 and 1", len(history.Posts), history.Truncated)
+	}
+	if !strings.HasPrefix(history.Posts[0].Text, "Second") {
+		t.Errorf("kept %q, want the newest entry", history.Posts[0].Text)
+	}
+}
+
+func TestChannelPostsUnknownChannel(t *testing.T) {
+
+	client, _, _ := newTestClient(t, http.StatusOK, rssFeed, 0)
+
+	_, err := client.ChannelPosts(context.Background(), entity.FeedChannelID("https://example.com/missing"), -1)
+	if !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("ChannelPosts() error = %v, want %v", err, ErrChannelNotFound)
+	}
+}
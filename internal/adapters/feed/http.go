@@ -0,0 +1,83 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+const maxRedirects = 3
+
+var (
+	ErrForbiddenAddress = errors.New("feed address is not public")
+
+	// shared address space of carrier-grade NAT, not covered by netip
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// NewHTTPClient fetches feeds on behalf of bot users, so it only connects
+// to public addresses. The check runs on the address actually dialed,
+// after DNS resolution and on every redirect, which DNS rebinding cannot
+// get around.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// a proxy would be dialed instead of the feed host
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 15 * time.Second,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to %s scheme", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+func checkAddress(address string) error {
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+
+	return nil
+}
+
+func isPublic(ip netip.Addr) bool {
+
+	ip = ip.Unmap()
+
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,63 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckAddress(t *testing.T) {
+
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.0.0.1:80", false},
+		{"172.16.5.4:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:80", false},
+		{"0.0.0.0:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"224.0.0.1:80", false},
+		{"example.com:80", false},
+		{"no-port", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+
+			err := checkAddress(tt.address)
+			if (err == nil) != tt.allowed {
+				t.Errorf("checkAddress(%q) error = %v, allowed %v", tt.address, err, tt.allowed)
+			}
+			if err != nil && !errors.Is(err, ErrForbiddenAddress) {
+				t.Errorf("checkAddress(%q) error = %v, want %v", tt.address, err, ErrForbiddenAddress)
+			}
+		})
+	}
+}
+
+func TestHTTPClientRejectsLoopback(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rssFeed))
+	}))
+	defer srv.Close()
+
+	client := NewFeedClient(NewHTTPClient(5*time.Second), &channelStore{}, &entryStore{}, 0)
+
+	_, err := client.ResolveFeed(context.Background(), srv.URL)
+	if !errors.Is(err, ErrFeedUnavailable) {
+		t.Errorf("ResolveFeed() error = %v, want %v", err, ErrFeedUnavailable)
+	}
+}
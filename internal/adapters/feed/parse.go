@@ -0,0 +1,273 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"hash/fnv"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	tagPattern   = regexp.MustCompile(`<[^>]*>`)
+	spacePattern = regexp.MustCompile(`[ \t]+`)
+
+	dateLayouts = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC3339,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"2 Jan 2006 15:04:05 -0700",
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+)
+
+// rssDocument covers RSS 2.0 and RSS 1.0 (RDF), where items are siblings
+// of the channel element.
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// parseFeed reads an RSS or Atom document into posts from newest to oldest.
+// Entries without a date cannot be ordered and are skipped.
+func parseFeed(channelID int64, data []byte) (string, []*entity.Post, error) {
+
+	root, err := rootElement(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var title string
+	var posts []*entity.Post
+
+	switch root {
+
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := decode(data, &doc); err != nil {
+			return "", nil, err
+		}
+
+		title = doc.Channel.Title
+		for _, item := range append(doc.Channel.Items, doc.Items...) {
+			body := item.Content
+			if body == "" {
+				body = item.Description
+			}
+			guid := item.GUID
+			if guid == "" {
+				guid = item.About
+			}
+			posts = appendPost(posts, channelID, guid, item.Title, body, strings.TrimSpace(item.Link), firstDate(item.PubDate, item.Date))
+		}
+
+	case "feed":
+		var doc atomDocument
+		if err := decode(data, &doc); err != nil {
+			return "", nil, err
+		}
+
+		title = doc.Title
+		for _, entry := range doc.Entries {
+			body := entry.Content
+			if body == "" {
+				body = entry.Summary
+			}
+			posts = appendPost(posts, channelID, entry.ID, entry.Title, body, entryLink(entry.Links), firstDate(entry.Published, entry.Updated))
+		}
+
+	default:
+		return "", nil, ErrFeedFormat
+	}
+
+	sortNewestFirst(posts)
+
+	return plainText(title), uniquePosts(posts), nil
+}
+
+func sortNewestFirst(posts []*entity.Post) {
+	slices.SortStableFunc(posts, func(a, b *entity.Post) int {
+		return b.Date.Compare(a.Date)
+	})
+}
+
+// uniquePosts drops repeated entries, feeds occasionally list an entry twice.
+func uniquePosts(posts []*entity.Post) []*entity.Post {
+
+	seen := make(map[int64]bool, len(posts))
+	unique := posts[:0]
+	for _, post := range posts {
+		if !seen[post.ID] {
+			seen[post.ID] = true
+			unique = append(unique, post)
+		}
+	}
+
+	return unique
+}
+
+func rootElement(data []byte) (string, error) {
+
+	decoder := newDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", ErrFeedFormat
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decode(data []byte, v any) error {
+
+	if err := newDecoder(data).Decode(v); err != nil {
+		return ErrFeedFormat
+	}
+	return nil
+}
+
+// newDecoder accepts legacy encodings such as windows-1251, which are
+// still common on news sites.
+func newDecoder(data []byte) *xml.Decoder {
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+
+	return decoder
+}
+
+func appendPost(posts []*entity.Post, channelID int64, guid, title, body, link string, date time.Time) []*entity.Post {
+
+	if date.IsZero() {
+		return posts
+	}
+
+	post := &entity.Post{
+		ChannelID: channelID,
+		ID:        entryID(guid, link, title, date),
+		Date:      date,
+		Link:      link,
+		Text:      strings.TrimSpace(plainText(title) + "\n" + plainText(body)),
+	}
+	if link != "" {
+		post.URLs = []string{link}
+	}
+
+	return append(posts, post)
+}
+
+// entryID derives a stable post ID from the entry's GUID, from its link
+// when the feed gives none, or from its title and date as a last resort.
+// Dates alone are not unique: entries share seconds and date-only feeds
+// share whole days.
+func entryID(guid, link, title string, date time.Time) int64 {
+
+	key := strings.TrimSpace(guid)
+	if key == "" {
+		key = link
+	}
+	if key == "" {
+		key = title + "\n" + date.UTC().Format(time.RFC3339)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	return int64(h.Sum64() >> 1)
+}
+
+func entryLink(links []atomLink) string {
+
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+
+	return ""
+}
+
+func firstDate(values ...string) time.Time {
+
+	for _, value := range values {
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		for _, layout := range dateLayouts {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// plainText strips markup from titles and descriptions, which feeds
+// usually carry as escaped HTML.
+func plainText(s string) string {
+
+	s = tagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(spacePattern.ReplaceAllString(line, " ")); line != "" {
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,124 @@
+package feed
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseFeed(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		data    string
+		title   string
+		texts   []string
+		wantErr error
+	}{
+		{
+			name: "rss 1.0",
+			data: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+				<channel><title>RDF</title></channel>
+				<item rdf:about="https://example.com/a"><title>A</title><dc:date>2025-06-02T09:00:00Z</dc:date></item>
+			</rdf:RDF>`,
+			title: "RDF",
+			texts: []string{"A"},
+		},
+		{
+			name: "content encoded over description",
+			data: `<rss xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel><title>T</title>
+				<item><title>A</title><description>short</description><content:encoded>full &amp;amp; rich</content:encoded><pubDate>2025-06-02</pubDate></item>
+			</channel></rss>`,
+			title: "T",
+			texts: []string{"A\nfull & rich"},
+		},
+		{
+			name: "undated entries skipped",
+			data: `<rss><channel><title>T</title>
+				<item><title>A</title><pubDate>Mon, 02 Jun 2025 09:00:00 GMT</pubDate></item>
+				<item><title>B</title></item>
+			</channel></rss>`,
+			title: "T",
+			texts: []string{"A"},
+		},
+		{
+			name: "duplicate guid",
+			data: `<rss><channel><title>T</title>
+				<item><guid>1</guid><title>A</title><pubDate>2025-06-02</pubDate></item>
+				<item><guid>1</guid><title>A</title><pubDate>2025-06-02</pubDate></item>
+			</channel></rss>`,
+			title: "T",
+			texts: []string{"A"},
+		},
+		{
+			name: "newest first",
+			data: `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title>
+				<entry><id>1</id><title>Old</title><published>2025-06-01T09:00:00Z</published></entry>
+				<entry><id>2</id><title>New</title><updated>2025-06-03T09:00:00Z</updated></entry>
+			</feed>`,
+			title: "T",
+			texts: []string{"New", "Old"},
+		},
+		{
+			name:    "unknown root",
+			data:    `<opml><body/></opml>`,
+			wantErr: ErrFeedFormat,
+		},
+		{
+			name:    "empty",
+			data:    ``,
+			wantErr: ErrFeedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			title, posts, err := parseFeed(-1, []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseFeed() error = %v, want %v", err, tt.wantErr)
+			}
+			if title != tt.title {
+				t.Errorf("title = %q, want %q", title, tt.title)
+			}
+			if len(posts) != len(tt.texts) {
+				t.Fatalf("got %d posts, want %d", len(posts), len(tt.texts))
+			}
+			for i, post := range posts {
+				if post.Text != tt.texts[i] {
+					t.Errorf("post %d text = %q, want %q", i, post.Text, tt.texts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEntryID(t *testing.T) {
+
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		a, b  [3]string
+		equal bool
+	}{
+		{"same guid, different link", [3]string{"g", "l1", "t"}, [3]string{"g", "l2", "t"}, true},
+		{"different guid", [3]string{"g1", "l", "t"}, [3]string{"g2", "l", "t"}, false},
+		{"link without guid", [3]string{"", "l", "t1"}, [3]string{"", "l", "t2"}, true},
+		{"same day, different title", [3]string{"", "", "t1"}, [3]string{"", "", "t2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			a := entryID(tt.a[0], tt.a[1], tt.a[2], date)
+			b := entryID(tt.b[0], tt.b[1], tt.b[2], date)
+			if (a == b) != tt.equal {
+				t.Errorf("entryID equal = %v, want %v", a == b, tt.equal)
+			}
+			if a < 0 || b < 0 {
+				t.Errorf("entryID is negative: %d, %d", a, b)
+			}
+		})
+	}
+}
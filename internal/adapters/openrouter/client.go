@@ -8,34 +8,16 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"post-analyzer/internal/domain/entity"
 )
 
 const (
 	defaultModel string = "deepseek/deepseek-chat-v3.1:free"
 	defaultURL   string = "https://openrouter.ai/api/v1/chat/completions"
 
-	userPrompt string = "Проанализируй следующие посты из Telegram-каналов и выдай краткую выжимку по заданным правилам:\n"
-	sysPrompt  string = `Ты — российский эксперт по анализу новостей из Telegram-каналов. Твоя задача — прочитать посты, проанализировать их содержание, отфильтровать кликбейт, слухи, эмоциональный шум, провокации и неподтверждённую информацию. Оставить только факты, подкреплённые достоверными данными.
-
-		Сделай краткую выжимку из новостей по следующим правилам:
-		- Только объективные, проверяемые факты.
-		- Убери оценки, мнения, гипотезы, предположения.
-		- Не используй метафоры, эмоциональные выражения, восклицания.
-		- Сократи текст до 1 предложения на каждую новость.
-		- Общее количество оставшихся новостей - не более 30% от изначального количества.
-		- Если новость непроверенная, малозначимая или похожа на слух — проигнорируй её.
-		- Группируй схожие новости в один пункт.
-		- Формат: маркированный список, каждый пункт — одна важная новость Между новостями - пустая строка.
-		- Анализ исключительно на русском языке.
-		- Учти, твоя работа оценивается очень строго.
-
-		Пример вывода:
-		- [Краткая суть новости, только факты]
-
-		- [Ещё одна новость, без лишних деталей]
-
-		- [...]
-		`
+	defaultTemperature float32 = 0.1
+	defaultTopP        float32 = 0.5
 )
 
 var (
@@ -51,8 +33,17 @@ var (
 	ErrTimeLimit = errors.New("openrouter req time limit reached")
 )
 
+// AnalysisRequest carries the posts to analyze along with the model
+// settings, unset settings fall back to the client defaults. The analysis
+// is written in Language, Russian when it is unset.
+type AnalysisRequest struct {
+	Posts    string
+	Options  entity.AnalysisOptions
+	Language entity.Language
+}
+
 type AnalysisService interface {
-	AnalyzePosts(ctx context.Context, text string) (string, error)
+	AnalyzePosts(ctx context.Context, ar *AnalysisRequest) (string, error)
 }
 
 type openRouterClient struct {
@@ -71,16 +62,24 @@ func NewOpenRouterClient(apikey string) *openRouterClient {
 	}
 }
 
-func (c openRouterClient) AnalyzePosts(ctx context.Context, text string) (string, error) {
+func (c openRouterClient) AnalyzePosts(ctx context.Context, ar *AnalysisRequest) (string, error) {
 
 	if err := ctx.Err(); err != nil {
 		return "", ErrTimeLimit
 	}
 
-	userPrompt := userPrompt + text
+	texts, ok := prompts[ar.Language]
+	if !ok {
+		texts = prompts[entity.DefaultLanguage]
+	}
+
+	userPrompt := texts.user + ar.Posts
+
+	options := ar.Options.Or(defaultOptions(texts.system))
+	sysPrompt := options.Prompt + "\n\n" + texts.output
 
 	reqBody := openRouterRequest{
-		Model: defaultModel,
+		Model: options.Model,
 		Messages: []message{
 			{Role: "system", Content: sysPrompt},
 			{Role: "user", Content: userPrompt},
@@ -89,8 +88,8 @@ func (c openRouterClient) AnalyzePosts(ctx context.Context, text string) (string
 			Enabled: false,
 		},
 		Verbosity:   "low",
-		Temperature: 0.1,
-		ToPP:        0.5,
+		Temperature: *options.Temperature,
+		ToPP:        *options.TopP,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -131,3 +130,14 @@ func (c openRouterClient) AnalyzePosts(ctx context.Context, text string) (string
 
 	return "", ErrEmptyResponse
 }
+
+func defaultOptions(prompt string) entity.AnalysisOptions {
+
+	temperature, topP := defaultTemperature, defaultTopP
+	return entity.AnalysisOptions{
+		Model:       defaultModel,
+		Temperature: &temperature,
+		TopP:        &topP,
+		Prompt:      prompt,
+	}
+}
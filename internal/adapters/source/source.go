@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+// Source delivers posts of a channel, whatever publishes them: a Telegram
+// channel or a web feed.
+type Source interface {
+	ChannelPosts(ctx context.Context, channelID int64, lastReadID int64) (*entity.ChannelHistory, error)
+	ChannelRecentPosts(ctx context.Context, channelID int64, since time.Time) (*entity.ChannelHistory, error)
+	LatestPostID(ctx context.Context, channelID int64) (int64, error)
+	Posts(ctx context.Context, channelID int64, postIDs []int64) (map[int64]*entity.Post, error)
+	Comments(ctx context.Context, channelID int64, postIDs []int64, limit int) (map[int64][]*entity.Post, error)
+	ChannelDetails(ctx context.Context, channelID int64) (*entity.Channel, error)
+	Watch(channelID int64)
+	Unwatch(channelID int64)
+}
+
+type router struct {
+	telegram Source
+	feeds    Source
+}
+
+// NewRouter serves feed channels from feeds and the rest from telegram.
+func NewRouter(telegram Source, feeds Source) Source {
+	return &router{
+		telegram: telegram,
+		feeds:    feeds,
+	}
+}
+
+func (r *router) source(channelID int64) Source {
+
+	if entity.IsFeedChannel(channelID) {
+		return r.feeds
+	}
+	return r.telegram
+}
+
+func (r *router) ChannelPosts(ctx context.Context, channelID int64, lastReadID int64) (*entity.ChannelHistory, error) {
+	return r.source(channelID).ChannelPosts(ctx, channelID, lastReadID)
+}
+
+func (r *router) ChannelRecentPosts(ctx context.Context, channelID int64, since time.Time) (*entity.ChannelHistory, error) {
+	return r.source(channelID).ChannelRecentPosts(ctx, channelID, since)
+}
+
+func (r *router) LatestPostID(ctx context.Context, channelID int64) (int64, error) {
+	return r.source(channelID).LatestPostID(ctx, channelID)
+}
+
+func (r *router) Posts(ctx context.Context, channelID int64, postIDs []int64) (map[int64]*entity.Post, error) {
+	return r.source(channelID).Posts(ctx, channelID, postIDs)
+}
+
+func (r *router) Comments(ctx context.Context, channelID int64, postIDs []int64, limit int) (map[int64][]*entity.Post, error) {
+	return r.source(channelID).Comments(ctx, channelID, postIDs, limit)
+}
+
+func (r *router) ChannelDetails(ctx context.Context, channelID int64) (*entity.Channel, error) {
+	return r.source(channelID).ChannelDetails(ctx, channelID)
+}
+
+func (r *router) Watch(channelID int64) {
+	r.source(channelID).Watch(channelID)
+}
+
+func (r *router) Unwatch(channelID int64) {
+	r.source(channelID).Unwatch(channelID)
+}
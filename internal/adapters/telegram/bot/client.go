@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -40,3 +41,24 @@ func (t TelegramBotClient) SendTextMessage(ctx context.Context, chatID int64, te
 
 	return msg, nil
 }
+
+func (t TelegramBotClient) SendPhoto(ctx context.Context, chatID int64, image []byte, caption string) (*models.Message, error) {
+
+	if ctx.Err() != nil {
+		return nil, ErrTimeLimit
+	}
+
+	msg, err := t.b.SendPhoto(ctx, &bot.SendPhotoParams{
+		ChatID: chatID,
+		Photo: &models.InputFileUpload{
+			Filename: "photo.png",
+			Data:     bytes.NewReader(image),
+		},
+		Caption: caption,
+	})
+	if err != nil {
+		return nil, ErrAPICallFailed
+	}
+
+	return msg, nil
+}
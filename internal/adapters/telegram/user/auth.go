@@ -1,33 +1,75 @@
 package user
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"image/png"
+	"time"
 
 	"github.com/gotd/td/telegram"
 	"github.com/gotd/td/telegram/auth"
+	"github.com/gotd/td/telegram/auth/qrlogin"
 	"github.com/gotd/td/tg"
+	"rsc.io/qr"
 )
 
-func (t telegramUserClient) authenticate(ctx context.Context) error {
+const (
+	AuthMethodCode = "code"
+	AuthMethodQR   = "qr"
 
-	if ctx.Err() != nil {
-		return ErrTimeLimit
-	}
+	loginTimeout = 10 * time.Minute
+)
 
-	authClient := t.createRawClient()
+var ErrUnknownAuthMethod = errors.New("unknown auth method")
 
-	return authClient.Run(ctx, func(ctx context.Context) error {
+// AuthPrompter reaches the operator when the session has to be authorized,
+// so the service can log in without a terminal.
+type AuthPrompter interface {
+	RequestCode(ctx context.Context) (string, error)
+	ShowQR(ctx context.Context, image []byte, expires time.Time) error
+	LoggedIn(ctx context.Context) error
+}
+
+type AuthOptions struct {
+	Method   string
+	Phone    string
+	Password string
+	Prompter AuthPrompter
+}
 
-		if ctx.Err() != nil {
-			return ErrTimeLimit
+// login authorizes the session with the configured method. Accounts with
+// 2FA finish the QR login with the configured password.
+func (t *telegramUserClient) login(ctx context.Context, client *telegram.Client, loggedIn qrlogin.LoggedIn) error {
+
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	var err error
+	switch t.auth.Method {
+
+	case AuthMethodCode, "":
+		err = client.Auth().IfNecessary(ctx, t.createAuthFlow())
+
+	case AuthMethodQR:
+		_, err = client.QR().Auth(ctx, loggedIn, t.showQR)
+		if errors.Is(err, auth.ErrPasswordAuthNeeded) {
+			_, err = client.Auth().Password(ctx, t.auth.Password)
 		}
 
-		return authClient.Auth().IfNecessary(ctx, t.createAuthFlow())
-	})
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownAuthMethod, t.auth.Method)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAuthFailed, err)
+	}
+
+	return t.auth.Prompter.LoggedIn(ctx)
 }
 
-func (t telegramUserClient) createRawClient() *telegram.Client {
+func (t *telegramUserClient) createRawClient(handler telegram.UpdateHandler) *telegram.Client {
 	return telegram.NewClient(
 		t.appID,
 		t.appHash,
@@ -35,41 +77,39 @@ func (t telegramUserClient) createRawClient() *telegram.Client {
 			SessionStorage: &telegram.FileSessionStorage{
 				Path: t.sessionPath,
 			},
+			Middlewares:   []telegram.Middleware{t.limiter},
+			UpdateHandler: handler,
 		})
 }
 
-func (t telegramUserClient) createAuthFlow() auth.Flow {
+func (t *telegramUserClient) createAuthFlow() auth.Flow {
 	return auth.NewFlow(
-		auth.Env("", auth.CodeAuthenticatorFunc(t.requestAuthCode)),
+		auth.Constant(t.auth.Phone, t.auth.Password, auth.CodeAuthenticatorFunc(t.requestAuthCode)),
 		auth.SendCodeOptions{},
 	)
 }
 
-func (t telegramUserClient) requestAuthCode(ctx context.Context, sentCode *tg.AuthSentCode) (string, error) {
+func (t *telegramUserClient) requestAuthCode(ctx context.Context, sentCode *tg.AuthSentCode) (string, error) {
 
-	codeChan := make(chan string, 1)
-	errChan := make(chan error, 1)
-
-	go func() {
+	code, err := t.auth.Prompter.RequestCode(ctx)
+	if ctx.Err() != nil {
+		return "", ErrTimeLimit
+	}
 
-		fmt.Print("Enter code from TGSupport chat: ")
-		var code string
+	return code, err
+}
 
-		if _, err := fmt.Scanln(&code); err != nil {
-			errChan <- err
-			return
-		}
+func (t *telegramUserClient) showQR(ctx context.Context, token qrlogin.Token) error {
 
-		codeChan <- code
-	}()
+	image, err := token.Image(qr.M)
+	if err != nil {
+		return err
+	}
 
-	select {
-	case <-ctx.Done():
-		return "", ErrTimeLimit
-	case err := <-errChan:
-		return "", err
-	case code := <-codeChan:
-		return code, nil
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image); err != nil {
+		return err
 	}
 
+	return t.auth.Prompter.ShowQR(ctx, buf.Bytes(), token.Expires())
 }
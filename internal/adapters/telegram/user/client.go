@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"time"
 
+	"post-analyzer/internal/adapters/source"
+	"post-analyzer/internal/domain/entity"
+
 	"github.com/gotd/td/tg"
 )
 
@@ -20,21 +24,58 @@ var (
 
 	ErrChannelNotFound = errors.New("no channel found")
 
+	ErrNoPosts = errors.New("no posts found")
+
 	ErrTimeLimit = errors.New("telegram user req time limit reached")
 )
 
+const (
+	maxPageSize     = 100
+	defaultMaxPosts = 500
+
+	channelInfoTTL = 24 * time.Hour
+)
+
 type TelegramService interface {
-	ChannelPosts(ctx context.Context, username string, lastReadID int64) ([]*tg.Message, error)
+	source.Source
 	ChannelInfo(ctx context.Context, username string) (*tg.Channel, error)
+	CheckInvite(ctx context.Context, hash string) (*tg.Channel, error)
+	PreviewInvite(ctx context.Context, hash string) (*tg.Channel, error)
+	JoinChannel(ctx context.Context, hash string) (*tg.Channel, error)
+}
+
+// ChannelStore caches resolved peers, so history is fetched by channel ID
+// without resolving usernames on every run, along with the full channel info.
+type ChannelStore interface {
+	GetChannel(ctx context.Context, channelID int64) (*entity.Channel, error)
+	SaveChannel(ctx context.Context, channel *entity.Channel) error
+	SaveChannelInfo(ctx context.Context, channel *entity.Channel) error
+}
+
+// HistoryLimits bounds how deep a single fetch pages into channel history.
+type HistoryLimits struct {
+	PageSize int
+	MaxPosts int
+	MaxAge   time.Duration
 }
 
 type telegramUserClient struct {
 	appID       int
 	appHash     string
 	sessionPath string
+	auth        AuthOptions
+	limits      HistoryLimits
+
+	store   ChannelStore
+	posts   PostStore
+	watched *watchList
+	limiter *rateLimiter
+	conn    *connection
 }
 
-func NewTelegramUserClient(appID int, appHash string, sessionPath string) (*telegramUserClient, error) {
+// NewTelegramUserClient starts the user session. A nil posts store
+// disables real-time ingestion and every fetch goes to Telegram.
+func NewTelegramUserClient(appID int, appHash string, sessionPath string, authOpts AuthOptions, limits HistoryLimits, rateLimits RateLimits, store ChannelStore, posts PostStore) (*telegramUserClient, error) {
 
 	if _, err := os.Stat(sessionPath); err != nil {
 		directory := filepath.Dir(sessionPath)
@@ -43,43 +84,131 @@ func NewTelegramUserClient(appID int, appHash string, sessionPath string) (*tele
 		}
 	}
 
+	if limits.PageSize <= 0 || limits.PageSize > maxPageSize {
+		limits.PageSize = maxPageSize
+	}
+	if limits.MaxPosts <= 0 {
+		limits.MaxPosts = defaultMaxPosts
+	}
+
 	client := &telegramUserClient{
 		appID:       appID,
 		appHash:     appHash,
 		sessionPath: sessionPath,
+		auth:        authOpts,
+		limits:      limits,
+		store:       store,
+		posts:       posts,
+		watched:     &watchList{channels: make(map[int64]watchState)},
+		limiter:     newRateLimiter(rateLimits),
+		conn:        newConnection(),
 	}
 
-	authCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := client.authenticate(authCtx); err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrAuthFailed, err)
-	}
+	// authorization happens inside the session loop, prompting the
+	// operator whenever the stored session is missing or revoked
+	client.connect()
 
 	return client, nil
 }
 
-func (t telegramUserClient) ChannelPosts(ctx context.Context, username string, lastReadID int64) ([]*tg.Message, error) {
+func (t *telegramUserClient) ChannelPosts(ctx context.Context, channelID int64, lastReadID int64) (*entity.ChannelHistory, error) {
+	return t.channelPosts(ctx, channelID, lastReadID, time.Time{})
+}
 
-	var posts []*tg.Message
+func (t *telegramUserClient) ChannelRecentPosts(ctx context.Context, channelID int64, since time.Time) (*entity.ChannelHistory, error) {
 
-	client := t.createRawClient()
+	history, err := t.channelPosts(ctx, channelID, -1, since)
+	if err != nil {
+		return nil, err
+	}
 
-	err := client.Run(ctx, func(ctx context.Context) error {
+	if len(history.Posts) == 0 {
+		return nil, ErrNoPosts
+	}
 
-		apiClient := tg.NewClient(client)
+	return history, nil
+}
 
-		channel, err := t.channel(ctx, apiClient, username)
-		if err != nil {
-			return err
+func (t *telegramUserClient) LatestPostID(ctx context.Context, channelID int64) (int64, error) {
+
+	if t.posts != nil && t.watched.ready(channelID) {
+		if latest, err := t.posts.LatestPostID(ctx, channelID); err == nil && latest > 0 {
+			return latest, nil
 		}
+	}
 
-		history, err := t.channelHistory(ctx, apiClient, channel, lastReadID)
-		if err != nil {
-			return err
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var page tg.MessagesMessagesClass
+	err = t.withPeer(ctx, apiClient, channelID, func(channel *entity.Channel) error {
+
+		page, err = apiClient.MessagesGetHistory(ctx,
+			&tg.MessagesGetHistoryRequest{
+				Peer:  inputPeer(channel),
+				Limit: 1,
+			})
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	messages, ok := page.(*tg.MessagesChannelMessages)
+	if !ok || len(messages.Messages) == 0 {
+		return 0, ErrNoPosts
+	}
+
+	return int64(messages.Messages[0].GetID()), nil
+}
+
+// Posts returns the current versions of the given posts by ID, deleted
+// posts are missing from the result.
+func (t *telegramUserClient) Posts(ctx context.Context, channelID int64, postIDs []int64) (map[int64]*entity.Post, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make(map[int64]*entity.Post, len(postIDs))
+	err = t.withPeer(ctx, apiClient, channelID, func(channel *entity.Channel) error {
+
+		for start := 0; start < len(postIDs); start += maxPageSize {
+
+			ids := make([]tg.InputMessageClass, 0, maxPageSize)
+			for _, id := range postIDs[start:min(start+maxPageSize, len(postIDs))] {
+				ids = append(ids, &tg.InputMessageID{ID: int(id)})
+			}
+
+			page, err := apiClient.ChannelsGetMessages(ctx,
+				&tg.ChannelsGetMessagesRequest{
+					Channel: &tg.InputChannel{
+						ChannelID:  channel.ID,
+						AccessHash: channel.AccessHash,
+					},
+					ID: ids,
+				})
+			if err != nil {
+				return err
+			}
+
+			messages, ok := page.(*tg.MessagesChannelMessages)
+			if !ok {
+				continue
+			}
+
+			// deleted posts come back as empty messages
+			for _, msg := range messages.Messages {
+				if m, ok := msg.(*tg.Message); ok {
+					posts[int64(m.ID)] = toPost(channel.ID, m, messages.Chats, messages.Users)
+				}
+			}
 		}
 
-		posts = t.filterMessages(history)
 		return nil
 	})
 
@@ -90,22 +219,144 @@ func (t telegramUserClient) ChannelPosts(ctx context.Context, username string, l
 	return posts, nil
 }
 
-func (t telegramUserClient) ChannelInfo(ctx context.Context, username string) (*tg.Channel, error) {
+// Comments returns up to limit latest comments to each of the given posts
+// from the linked discussion group, oldest first. Posts without a comment
+// thread are missing from the result.
+func (t *telegramUserClient) Comments(ctx context.Context, channelID int64, postIDs []int64, limit int) (map[int64][]*entity.Post, error) {
 
-	var channel *tg.Channel
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make(map[int64][]*entity.Post, len(postIDs))
+	err = t.withPeer(ctx, apiClient, channelID, func(channel *entity.Channel) error {
+
+		for _, postID := range postIDs {
+
+			if ctx.Err() != nil {
+				return ErrTimeLimit
+			}
+
+			page, err := apiClient.MessagesGetReplies(ctx,
+				&tg.MessagesGetRepliesRequest{
+					Peer:  inputPeer(channel),
+					MsgID: int(postID),
+					Limit: min(limit, maxPageSize),
+				})
+			if tg.IsMsgIDInvalid(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			messages, ok := page.(*tg.MessagesChannelMessages)
+			if !ok {
+				continue
+			}
+
+			// replies come from newest to oldest and live in the discussion group
+			for i := len(messages.Messages) - 1; i >= 0; i-- {
+
+				m, ok := messages.Messages[i].(*tg.Message)
+				if !ok {
+					continue
+				}
+				group, ok := m.PeerID.(*tg.PeerChannel)
+				if !ok {
+					continue
+				}
+
+				if comment := toPost(group.ChannelID, m, messages.Chats, messages.Users); !comment.IsEmpty() {
+					comments[postID] = append(comments[postID], comment)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (t *telegramUserClient) ChannelInfo(ctx context.Context, username string) (*tg.Channel, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.channel(ctx, apiClient, username)
+}
+
+// ChannelDetails returns the channel with its full info, which is fetched
+// again once it is older than channelInfoTTL. A stale copy is returned when
+// the refresh fails.
+func (t *telegramUserClient) ChannelDetails(ctx context.Context, channelID int64) (*entity.Channel, error) {
+
+	cached, err := t.store.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNotFound, err)
+	}
 
-	client := t.createRawClient()
+	if time.Since(cached.InfoUpdatedAt) < channelInfoTTL {
+		return cached, nil
+	}
 
-	err := client.Run(ctx, func(ctx context.Context) error {
+	channel, err := t.fullChannel(ctx, channelID)
+	if err != nil {
+		if cached.InfoUpdatedAt.IsZero() {
+			return nil, err
+		}
+		log.Printf("failed to refresh info of channel %d: %v", channelID, err)
+		return cached, nil
+	}
 
-		apiClient := tg.NewClient(client)
+	return channel, nil
+}
 
-		var err error
-		channel, err = t.channel(ctx, apiClient, username)
+func (t *telegramUserClient) fullChannel(ctx context.Context, channelID int64) (*entity.Channel, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var channel *entity.Channel
+	err = t.withPeer(ctx, apiClient, channelID, func(cached *entity.Channel) error {
+
+		result, err := apiClient.ChannelsGetFullChannel(ctx, &tg.InputChannel{
+			ChannelID:  cached.ID,
+			AccessHash: cached.AccessHash,
+		})
 		if err != nil {
 			return err
 		}
 
+		full, ok := result.FullChat.(*tg.ChannelFull)
+		if !ok {
+			return ErrChannelNotFound
+		}
+
+		t.refreshUsername(ctx, cached, result.Chats)
+
+		channel = cached
+		channel.About = full.About
+		channel.Participants, _ = full.GetParticipantsCount()
+		channel.LinkedChatID, _ = full.GetLinkedChatID()
+		channel.InfoUpdatedAt = time.Now()
+
+		for _, chat := range result.Chats {
+			if c, ok := chat.(*tg.Channel); ok && c.ID == channel.ID {
+				channel.Username, channel.Title = c.Username, c.Title
+			}
+		}
+
 		return nil
 	})
 
@@ -113,10 +364,107 @@ func (t telegramUserClient) ChannelInfo(ctx context.Context, username string) (*
 		return nil, err
 	}
 
+	if err := t.store.SaveChannelInfo(ctx, channel); err != nil {
+		log.Printf("failed to save info of channel %d: %v", channelID, err)
+	}
+
 	return channel, nil
 }
 
-func (t telegramUserClient) channel(ctx context.Context, api *tg.Client, username string) (*tg.Channel, error) {
+func (t *telegramUserClient) channelPosts(ctx context.Context, channelID int64, lastReadID int64, since time.Time) (*entity.ChannelHistory, error) {
+
+	if t.limits.MaxAge > 0 {
+		if oldest := time.Now().Add(-t.limits.MaxAge); oldest.After(since) {
+			since = oldest
+		}
+	}
+
+	var history *entity.ChannelHistory
+	var err error
+
+	if t.posts != nil && t.watched.covers(channelID, lastReadID, since) {
+		history, err = t.storedHistory(ctx, channelID, lastReadID, since)
+	} else {
+		history, err = t.fetchHistory(ctx, channelID, lastReadID, since)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	history.Posts = publishedPosts(history.Posts)
+	return history, nil
+}
+
+func (t *telegramUserClient) fetchHistory(ctx context.Context, channelID int64, lastReadID int64, since time.Time) (*entity.ChannelHistory, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var history *entity.ChannelHistory
+	err = t.withPeer(ctx, apiClient, channelID, func(channel *entity.Channel) error {
+		history, err = t.channelHistory(ctx, apiClient, channel, lastReadID, since)
+		return err
+	})
+
+	return history, err
+}
+
+// publishedPosts merges albums and drops media without any text.
+func publishedPosts(posts []*entity.Post) []*entity.Post {
+
+	var published []*entity.Post
+	for _, post := range entity.MergeAlbums(posts) {
+		if !post.IsEmpty() {
+			published = append(published, post)
+		}
+	}
+
+	return published
+}
+
+// withPeer runs call with the cached peer and, when Telegram rejects its
+// access hash, resolves the channel by username once and retries.
+func (t *telegramUserClient) withPeer(ctx context.Context, api *tg.Client, channelID int64, call func(*entity.Channel) error) error {
+
+	if ctx.Err() != nil {
+		return ErrTimeLimit
+	}
+
+	channel, err := t.store.GetChannel(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrChannelNotFound, err)
+	}
+
+	if channel.AccessHash != 0 {
+		err = call(channel)
+		if !tg.IsChannelInvalid(err) {
+			return apiError(err)
+		}
+	}
+
+	// private channels have no username to resolve them by
+	if channel.Username == "" {
+		return ErrChannelNotFound
+	}
+
+	resolved, err := t.channel(ctx, api, channel.Username)
+	if err != nil {
+		return err
+	}
+
+	// the username now belongs to another channel
+	if resolved.ID != channelID {
+		return ErrChannelNotFound
+	}
+
+	channel.AccessHash = resolved.AccessHash
+	return apiError(call(channel))
+}
+
+func (t *telegramUserClient) channel(ctx context.Context, api *tg.Client, username string) (*tg.Channel, error) {
 
 	if ctx.Err() != nil {
 		return nil, ErrTimeLimit
@@ -131,52 +479,164 @@ func (t telegramUserClient) channel(ctx context.Context, api *tg.Client, usernam
 		return nil, ErrChannelNotFound
 	}
 
-	channel, ok := resolved.Chats[0].(*tg.Channel)
+	// usernames of users and basic groups resolve too, the result may also
+	// carry other chats, so the channel is picked by the resolved peer
+	peer, ok := resolved.Peer.(*tg.PeerChannel)
 	if !ok {
 		return nil, ErrChannelNotFound
 	}
 
-	return channel, nil
-}
+	for _, chat := range resolved.Chats {
 
-func (t telegramUserClient) channelHistory(ctx context.Context, api *tg.Client, channel *tg.Channel, lastReadID int64) (tg.MessagesMessagesClass, error) {
+		channel, ok := chat.(*tg.Channel)
+		if !ok || channel.ID != peer.ChannelID {
+			continue
+		}
 
-	if ctx.Err() != nil {
-		return nil, ErrTimeLimit
+		t.saveChannel(ctx, channel)
+		return channel, nil
 	}
 
-	history, err := api.MessagesGetHistory(ctx,
-		&tg.MessagesGetHistoryRequest{
-			Peer: &tg.InputPeerChannel{
-				ChannelID:  channel.ID,
-				AccessHash: channel.AccessHash,
-			},
-			Limit: 30,
-			MinID: int(lastReadID),
-		})
+	return nil, ErrChannelNotFound
+}
+
+// saveChannel is best effort: a missing cache entry only costs
+// another username resolution.
+func (t *telegramUserClient) saveChannel(ctx context.Context, channel *tg.Channel) {
+
+	err := t.store.SaveChannel(ctx, &entity.Channel{
+		ID:         channel.ID,
+		AccessHash: channel.AccessHash,
+		Username:   channel.Username,
+		Title:      channel.Title,
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrAPICallFailed, err)
+		log.Printf("failed to cache channel %d: %v", channel.ID, err)
 	}
-
-	return history, nil
 }
 
-func (t telegramUserClient) filterMessages(history tg.MessagesMessagesClass) []*tg.Message {
+// refreshUsername keeps the cached username in sync with the channel
+// returned alongside its history.
+func (t *telegramUserClient) refreshUsername(ctx context.Context, cached *entity.Channel, chats []tg.ChatClass) {
 
-	messages, ok := history.(*tg.MessagesChannelMessages)
-	if !ok {
-		return make([]*tg.Message, 0)
+	for _, chat := range chats {
+
+		channel, ok := chat.(*tg.Channel)
+		if !ok || channel.ID != cached.ID {
+			continue
+		}
+
+		if channel.Username != cached.Username || channel.Title != cached.Title {
+			if channel.AccessHash == 0 {
+				channel.AccessHash = cached.AccessHash
+			}
+			t.saveChannel(ctx, channel)
+		}
+		return
 	}
+}
+
+// channelHistory pages backwards from the newest message until lastReadID,
+// the since boundary or the MaxPosts cap is reached.
+func (t *telegramUserClient) channelHistory(ctx context.Context, api *tg.Client, channel *entity.Channel, lastReadID int64, since time.Time) (*entity.ChannelHistory, error) {
+
+	history := &entity.ChannelHistory{}
+	offsetID := 0
+
+	for {
+
+		if ctx.Err() != nil {
+			return nil, ErrTimeLimit
+		}
+
+		limit := min(t.limits.PageSize, t.limits.MaxPosts-history.Fetched)
+		if limit <= 0 {
+			history.Limited = true
+			history.Truncated = skippedMessages(int64(offsetID), lastReadID, since)
+			return history, nil
+		}
+
+		page, err := api.MessagesGetHistory(ctx,
+			&tg.MessagesGetHistoryRequest{
+				Peer:     inputPeer(channel),
+				OffsetID: offsetID,
+				Limit:    limit,
+				MinID:    int(lastReadID),
+			})
+
+		if err != nil {
+			return nil, err
+		}
 
-	var posts []*tg.Message
-	for _, msg := range messages.Messages {
-		if m, ok := msg.(*tg.Message); ok {
-			if m.Message != "" {
-				posts = append(posts, m)
+		messages, ok := page.(*tg.MessagesChannelMessages)
+		if !ok || len(messages.Messages) == 0 {
+			return history, nil
+		}
+
+		if offsetID == 0 {
+			t.refreshUsername(ctx, channel, messages.Chats)
+		}
+
+		for _, msg := range messages.Messages {
+
+			if date, ok := messageDate(msg); ok && !since.IsZero() && date.Before(since) {
+				return history, nil
+			}
+
+			history.Fetched++
+			offsetID = msg.GetID()
+
+			if m, ok := msg.(*tg.Message); ok {
+				history.Posts = append(history.Posts, toPost(channel.ID, m, messages.Chats, messages.Users))
 			}
 		}
+
+		if len(messages.Messages) < limit {
+			return history, nil
+		}
+	}
+}
+
+func inputPeer(channel *entity.Channel) *tg.InputPeerChannel {
+	return &tg.InputPeerChannel{
+		ChannelID:  channel.ID,
+		AccessHash: channel.AccessHash,
+	}
+}
+
+// apiError wraps raw RPC errors, leaving the package's own errors as is.
+func apiError(err error) error {
+
+	var floodErr *FloodWaitError
+	if err == nil || errors.Is(err, ErrTimeLimit) || errors.As(err, &floodErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrAPICallFailed, err)
+}
+
+func messageDate(msg tg.MessageClass) (time.Time, bool) {
+
+	switch m := msg.(type) {
+	case *tg.Message:
+		return time.Unix(int64(m.Date), 0), true
+	case *tg.MessageService:
+		return time.Unix(int64(m.Date), 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// skippedMessages counts the messages between the last read one and the
+// oldest one fetched. Message IDs in a channel are sequential, so the gap is
+// exact when reading from a known message without a time window; otherwise
+// some of the gap may be older than the window and the count is unknown.
+func skippedMessages(oldestID, lastReadID int64, since time.Time) int {
+
+	if lastReadID <= 0 || !since.IsZero() {
+		return 0
 	}
 
-	return posts
+	return int(max(0, oldestID-lastReadID-1))
 }
@@ -0,0 +1,168 @@
+package user
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/telegram/auth/qrlogin"
+	"github.com/gotd/td/telegram/updates"
+	"github.com/gotd/td/tg"
+)
+
+const (
+	minReconnectDelay = 5 * time.Second
+	maxReconnectDelay = 2 * time.Minute
+)
+
+// connection keeps a single MTProto session alive for the process lifetime
+// and hands out its API client to concurrent callers.
+type connection struct {
+	mu    sync.Mutex
+	api   *tg.Client
+	ready chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newConnection() *connection {
+	return &connection{
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+func (c *connection) setAPI(api *tg.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if api != nil {
+		c.api = api
+		close(c.ready)
+		return
+	}
+
+	if c.api != nil {
+		c.api = nil
+		c.ready = make(chan struct{})
+	}
+}
+
+func (c *connection) client(ctx context.Context) (*tg.Client, error) {
+
+	for {
+		c.mu.Lock()
+		api, ready := c.api, c.ready
+		c.mu.Unlock()
+
+		if api != nil {
+			return api, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrTimeLimit
+		case <-ready:
+		}
+	}
+}
+
+func (t *telegramUserClient) connect() {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.conn.cancel = cancel
+
+	go func() {
+		defer close(t.conn.done)
+
+		delay := minReconnectDelay
+		for {
+
+			started := time.Now()
+			err := t.runSession(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			// a session that stayed up for a while resets the backoff
+			if time.Since(started) > maxReconnectDelay {
+				delay = minReconnectDelay
+			}
+
+			log.Printf("telegram user client disconnected, reconnecting in %s: %v", delay, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}()
+}
+
+func (t *telegramUserClient) runSession(ctx context.Context) error {
+
+	dispatcher := tg.NewUpdateDispatcher()
+	loggedIn := qrlogin.OnLoginToken(dispatcher)
+
+	// the updates manager keeps its state in memory, gaps from before
+	// this session are covered by backfilling the watched channels
+	var manager *updates.Manager
+	var handler telegram.UpdateHandler = dispatcher
+	if t.posts != nil {
+		manager = t.newUpdateManager(dispatcher)
+		handler = manager
+	}
+
+	client := t.createRawClient(handler)
+
+	return client.Run(ctx, func(ctx context.Context) error {
+
+		if err := t.authorize(ctx, client, loggedIn); err != nil {
+			return err
+		}
+
+		t.conn.setAPI(client.API())
+		defer t.conn.setAPI(nil)
+
+		if manager == nil {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		self, err := client.Self(ctx)
+		if err != nil {
+			return err
+		}
+
+		return manager.Run(ctx, client.API(), self.ID, updates.AuthOptions{
+			OnStart: func(ctx context.Context) {
+				go t.backfillWatched(ctx, client.API())
+				go t.cleanupPosts(ctx)
+			},
+		})
+	})
+}
+
+func (t *telegramUserClient) authorize(ctx context.Context, client *telegram.Client, loggedIn qrlogin.LoggedIn) error {
+
+	status, err := client.Auth().Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Authorized {
+		return nil
+	}
+
+	return t.login(ctx, client, loggedIn)
+}
+
+func (t *telegramUserClient) Stop() {
+	t.conn.cancel()
+	<-t.conn.done
+}
@@ -0,0 +1,402 @@
+package user
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+
+	"github.com/gotd/td/telegram/updates"
+	"github.com/gotd/td/tg"
+)
+
+const (
+	watchTimeout    = 2 * time.Minute
+	cleanupInterval = time.Hour
+)
+
+// PostStore keeps posts received in real time, so digests are built
+// without paging through channel history at send time.
+type PostStore interface {
+	SavePosts(ctx context.Context, posts []*entity.Post) error
+	GetPosts(ctx context.Context, channelID int64, afterID int64, since time.Time, limit int) ([]*entity.Post, error)
+	LatestPostID(ctx context.Context, channelID int64) (int64, error)
+	DeletePosts(ctx context.Context, channelID int64, postIDs []int64) error
+	DeletePostsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// watchList tracks ingested channels. A channel is read from the store
+// only once its history since the last stored post has been backfilled.
+// A backfill cut short by the fetch limit leaves a gap below the oldest
+// post it stored, reads reaching into the gap still go to Telegram.
+type watchList struct {
+	mu       sync.Mutex
+	channels map[int64]watchState
+}
+
+type watchState struct {
+	ready bool
+
+	// the oldest post stored by the latest truncated backfill
+	gapID   int64
+	gapDate time.Time
+}
+
+func (w *watchList) add(channelID int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.channels[channelID]; ok {
+		return false
+	}
+	w.channels[channelID] = watchState{}
+	return true
+}
+
+func (w *watchList) remove(channelID int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.channels, channelID)
+}
+
+// setReady marks the backfill of a watched channel done or pending. A
+// backfill finishing after the channel was unwatched changes nothing.
+func (w *watchList) setReady(channelID int64, ready bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.channels[channelID]
+	if !ok {
+		return
+	}
+	state.ready = ready
+	w.channels[channelID] = state
+}
+
+// setGap records that posts older than oldest may be missing from the store.
+// Older gaps are covered by the newer one.
+func (w *watchList) setGap(channelID int64, oldest *entity.Post) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.channels[channelID]
+	if !ok {
+		return
+	}
+	if oldest.ID > state.gapID {
+		state.gapID, state.gapDate = oldest.ID, oldest.Date
+	}
+	w.channels[channelID] = state
+}
+
+func (w *watchList) contains(channelID int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.channels[channelID]
+	return ok
+}
+
+func (w *watchList) ready(channelID int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.channels[channelID].ready
+}
+
+// covers reports whether the store holds every post newer than lastReadID
+// and not older than since.
+func (w *watchList) covers(channelID int64, lastReadID int64, since time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state := w.channels[channelID]
+	if !state.ready || state.gapID == 0 {
+		return state.ready
+	}
+
+	return lastReadID >= state.gapID-1 || since.After(state.gapDate)
+}
+
+func (w *watchList) ids() []int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]int64, 0, len(w.channels))
+	for id := range w.channels {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Watch joins the channel and starts storing its posts as they arrive.
+// It is a no-op when real-time ingestion is disabled.
+func (t *telegramUserClient) Watch(channelID int64) {
+
+	if t.posts == nil || !t.watched.add(channelID) {
+		return
+	}
+
+	go func() {
+
+		ctx, cancel := context.WithTimeout(context.Background(), watchTimeout)
+		defer cancel()
+
+		apiClient, err := t.conn.client(ctx)
+		if err != nil {
+			log.Printf("failed to watch channel %d: %v", channelID, err)
+			return
+		}
+
+		if err := t.join(ctx, apiClient, channelID); err != nil {
+			log.Printf("failed to join channel %d: %v", channelID, err)
+			return
+		}
+
+		t.backfill(ctx, apiClient, channelID)
+	}()
+}
+
+// Unwatch stops storing posts of the channel as they arrive. The account
+// stays in the channel, so watching it again needs no new invite.
+func (t *telegramUserClient) Unwatch(channelID int64) {
+	t.watched.remove(channelID)
+}
+
+func (t *telegramUserClient) join(ctx context.Context, api *tg.Client, channelID int64) error {
+
+	return t.withPeer(ctx, api, channelID, func(channel *entity.Channel) error {
+
+		_, err := api.ChannelsJoinChannel(ctx, &tg.InputChannel{
+			ChannelID:  channel.ID,
+			AccessHash: channel.AccessHash,
+		})
+		if tg.IsUserAlreadyParticipant(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// backfill stores posts published since the last stored one, covering the
+// time before the channel was watched and any disconnects.
+func (t *telegramUserClient) backfill(ctx context.Context, api *tg.Client, channelID int64) {
+
+	latest, err := t.posts.LatestPostID(ctx, channelID)
+	if err != nil {
+		log.Printf("failed to backfill channel %d: %v", channelID, err)
+		return
+	}
+
+	since := time.Time{}
+	if t.limits.MaxAge > 0 {
+		since = time.Now().Add(-t.limits.MaxAge)
+	}
+
+	var history *entity.ChannelHistory
+	err = t.withPeer(ctx, api, channelID, func(channel *entity.Channel) error {
+		history, err = t.channelHistory(ctx, api, channel, latest, since)
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to backfill channel %d: %v", channelID, err)
+		return
+	}
+
+	if posts := storablePosts(history.Posts); len(posts) > 0 {
+		if err := t.posts.SavePosts(ctx, posts); err != nil {
+			log.Printf("failed to backfill channel %d: %v", channelID, err)
+			return
+		}
+	}
+
+	// a truncated backfill leaves a gap below its oldest post, reads
+	// reaching into it keep being fetched from Telegram
+	if history.Limited && len(history.Posts) > 0 {
+		log.Printf("backfill of channel %d stopped at the limit of %d messages", channelID, t.limits.MaxPosts)
+		t.watched.setGap(channelID, history.Posts[len(history.Posts)-1])
+	}
+
+	t.watched.setReady(channelID, true)
+}
+
+func (t *telegramUserClient) backfillWatched(ctx context.Context, api *tg.Client) {
+
+	// posts missed while disconnected are fetched from Telegram until
+	// the backfill is done
+	channelIDs := t.watched.ids()
+	for _, channelID := range channelIDs {
+		t.watched.setReady(channelID, false)
+	}
+
+	for _, channelID := range channelIDs {
+
+		watchCtx, cancel := context.WithTimeout(ctx, watchTimeout)
+		t.backfill(watchCtx, api, channelID)
+		cancel()
+	}
+}
+
+func (t *telegramUserClient) onNewChannelMessage(ctx context.Context, e tg.Entities, update *tg.UpdateNewChannelMessage) error {
+	t.storeMessage(ctx, e, update.Message)
+	return nil
+}
+
+// onEditChannelMessage overwrites the stored post, so digests built from
+// the store carry the corrected text.
+func (t *telegramUserClient) onEditChannelMessage(ctx context.Context, e tg.Entities, update *tg.UpdateEditChannelMessage) error {
+	t.storeMessage(ctx, e, update.Message)
+	return nil
+}
+
+func (t *telegramUserClient) onDeleteChannelMessages(ctx context.Context, _ tg.Entities, update *tg.UpdateDeleteChannelMessages) error {
+
+	if !t.watched.contains(update.ChannelID) {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(update.Messages))
+	for _, id := range update.Messages {
+		ids = append(ids, int64(id))
+	}
+
+	if err := t.posts.DeletePosts(ctx, update.ChannelID, ids); err != nil {
+		log.Printf("failed to delete posts of channel %d: %v", update.ChannelID, err)
+	}
+
+	return nil
+}
+
+func (t *telegramUserClient) storeMessage(ctx context.Context, e tg.Entities, message tg.MessageClass) {
+
+	msg, ok := message.(*tg.Message)
+	if !ok {
+		return
+	}
+
+	peer, ok := msg.PeerID.(*tg.PeerChannel)
+	if !ok || !t.watched.contains(peer.ChannelID) {
+		return
+	}
+
+	chats := make([]tg.ChatClass, 0, len(e.Channels))
+	for _, channel := range e.Channels {
+		chats = append(chats, channel)
+	}
+
+	users := make([]tg.UserClass, 0, len(e.Users))
+	for _, user := range e.Users {
+		users = append(users, user)
+	}
+
+	post := toPost(peer.ChannelID, msg, chats, users)
+	if post.IsEmpty() {
+		return
+	}
+
+	// a failed write must not stop the update loop, the next backfill
+	// picks the post up again
+	if err := t.posts.SavePosts(ctx, []*entity.Post{post}); err != nil {
+		log.Printf("failed to store post %d of channel %d: %v", msg.ID, peer.ChannelID, err)
+	}
+}
+
+func (t *telegramUserClient) cleanupPosts(ctx context.Context) {
+
+	if t.limits.MaxAge <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := t.posts.DeletePostsBefore(ctx, time.Now().Add(-t.limits.MaxAge)); err != nil {
+			log.Printf("failed to clean up stored posts: %v", err)
+		}
+	}
+}
+
+// storedHistory reads posts from the store in the same shape channelHistory
+// returns them from Telegram.
+func (t *telegramUserClient) storedHistory(ctx context.Context, channelID int64, lastReadID int64, since time.Time) (*entity.ChannelHistory, error) {
+
+	posts, err := t.posts.GetPosts(ctx, channelID, max(lastReadID, 0), since, t.limits.MaxPosts+1)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &entity.ChannelHistory{}
+	if len(posts) > t.limits.MaxPosts {
+		posts = posts[:t.limits.MaxPosts]
+		history.Limited = true
+		history.Truncated = skippedMessages(posts[len(posts)-1].ID, lastReadID, since)
+	}
+
+	history.Posts = posts
+	history.Fetched = len(posts)
+
+	return history, nil
+}
+
+// storablePosts drops media without any text. Album parts are stored
+// separately and merged when read.
+func storablePosts(posts []*entity.Post) []*entity.Post {
+
+	var storable []*entity.Post
+	for _, post := range posts {
+		if !post.IsEmpty() {
+			storable = append(storable, post)
+		}
+	}
+
+	return storable
+}
+
+// accessHasher lets the updates manager recover channel gaps with the
+// peers cached in the channel store.
+type accessHasher struct {
+	store ChannelStore
+}
+
+func (h accessHasher) GetChannelAccessHash(ctx context.Context, _, channelID int64) (int64, bool, error) {
+
+	channel, err := h.store.GetChannel(ctx, channelID)
+	if err != nil || channel.AccessHash == 0 {
+		return 0, false, nil
+	}
+
+	return channel.AccessHash, true, nil
+}
+
+func (h accessHasher) SetChannelAccessHash(ctx context.Context, _, channelID, accessHash int64) error {
+
+	channel, err := h.store.GetChannel(ctx, channelID)
+	if err != nil || channel.AccessHash == accessHash {
+		return nil
+	}
+
+	channel.AccessHash = accessHash
+	return h.store.SaveChannel(ctx, channel)
+}
+
+func (t *telegramUserClient) newUpdateManager(dispatcher tg.UpdateDispatcher) *updates.Manager {
+
+	dispatcher.OnNewChannelMessage(t.onNewChannelMessage)
+	dispatcher.OnEditChannelMessage(t.onEditChannelMessage)
+	dispatcher.OnDeleteChannelMessages(t.onDeleteChannelMessages)
+
+	return updates.New(updates.Config{
+		Handler:      dispatcher,
+		AccessHasher: accessHasher{store: t.store},
+	})
+}
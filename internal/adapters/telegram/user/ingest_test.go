@@ -0,0 +1,90 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+func TestSkippedMessages(t *testing.T) {
+
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		oldestID   int64
+		lastReadID int64
+		since      time.Time
+		want       int
+	}{
+		{"gap to last read", 600, 100, time.Time{}, 499},
+		{"no gap", 101, 100, time.Time{}, 0},
+		{"nothing read yet", 600, -1, time.Time{}, 0},
+		{"zero position", 600, 0, time.Time{}, 0},
+		{"time window", 600, 100, since, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := skippedMessages(tt.oldestID, tt.lastReadID, tt.since); got != tt.want {
+				t.Errorf("skippedMessages() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchListCovers(t *testing.T) {
+
+	gapDate := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	watched := &watchList{channels: make(map[int64]watchState)}
+	watched.add(1)
+	watched.add(2)
+	watched.setReady(2, true)
+	watched.add(3)
+	watched.setReady(3, true)
+	watched.setGap(3, &entity.Post{ID: 500, Date: gapDate})
+
+	tests := []struct {
+		name       string
+		channelID  int64
+		lastReadID int64
+		since      time.Time
+		want       bool
+	}{
+		{"not backfilled", 1, 100, time.Time{}, false},
+		{"backfilled", 2, -1, time.Time{}, true},
+		{"read into the gap", 3, 100, time.Time{}, false},
+		{"read from the gap edge", 3, 499, time.Time{}, true},
+		{"window before the gap", 3, -1, gapDate.Add(-time.Hour), false},
+		{"window after the gap", 3, -1, gapDate.Add(time.Hour), true},
+		{"unknown channel", 4, 100, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := watched.covers(tt.channelID, tt.lastReadID, tt.since); got != tt.want {
+				t.Errorf("covers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A backfill that finishes after the channel was unwatched must not bring
+// it back into the watch list.
+func TestWatchListRemove(t *testing.T) {
+
+	watched := &watchList{channels: make(map[int64]watchState)}
+	watched.add(1)
+	watched.remove(1)
+	watched.setGap(1, &entity.Post{ID: 500})
+	watched.setReady(1, true)
+
+	if watched.contains(1) || watched.covers(1, -1, time.Time{}) {
+		t.Errorf("channel is watched after removal")
+	}
+	if !watched.add(1) {
+		t.Errorf("removed channel cannot be watched again")
+	}
+}
@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gotd/td/tg"
+)
+
+var (
+	ErrInviteInvalid     = errors.New("invite link is invalid or expired")
+	ErrInviteRequestSent = errors.New("join request sent to channel admins")
+	ErrNotChannel        = errors.New("invite link does not lead to a channel")
+)
+
+// CheckInvite returns the channel behind an invite link the account has
+// already joined, without joining anything.
+func (t *telegramUserClient) CheckInvite(ctx context.Context, hash string) (*tg.Channel, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := t.checkInvite(ctx, apiClient, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if already, ok := invite.(*tg.ChatInviteAlready); ok {
+		return t.inviteChannel(ctx, already.Chat)
+	}
+
+	return nil, ErrChannelNotFound
+}
+
+// PreviewInvite returns the channel behind an invite link without joining
+// it. A channel the account has not joined yet carries only its title.
+func (t *telegramUserClient) PreviewInvite(ctx context.Context, hash string) (*tg.Channel, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := t.checkInvite(ctx, apiClient, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch invite := invite.(type) {
+	case *tg.ChatInviteAlready:
+		return t.inviteChannel(ctx, invite.Chat)
+	case *tg.ChatInvite:
+		if !invite.Channel {
+			return nil, ErrNotChannel
+		}
+		return &tg.Channel{Title: invite.Title}, nil
+	}
+
+	return nil, ErrChannelNotFound
+}
+
+// JoinChannel joins the channel behind an invite link unless the account
+// is already a member, and caches its peer.
+func (t *telegramUserClient) JoinChannel(ctx context.Context, hash string) (*tg.Channel, error) {
+
+	apiClient, err := t.conn.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := t.checkInvite(ctx, apiClient, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch invite := invite.(type) {
+	case *tg.ChatInviteAlready:
+		return t.inviteChannel(ctx, invite.Chat)
+	case *tg.ChatInvite:
+		if !invite.Channel {
+			return nil, ErrNotChannel
+		}
+	}
+
+	updates, err := apiClient.MessagesImportChatInvite(ctx, hash)
+	switch {
+	case tg.IsInviteRequestSent(err):
+		return nil, ErrInviteRequestSent
+	case tg.IsInviteHashExpired(err), tg.IsInviteHashInvalid(err):
+		return nil, ErrInviteInvalid
+	case err != nil:
+		return nil, apiError(err)
+	}
+
+	joined, ok := updates.(interface{ GetChats() []tg.ChatClass })
+	if !ok || len(joined.GetChats()) == 0 {
+		return nil, ErrChannelNotFound
+	}
+
+	return t.inviteChannel(ctx, joined.GetChats()[0])
+}
+
+func (t *telegramUserClient) checkInvite(ctx context.Context, api *tg.Client, hash string) (tg.ChatInviteClass, error) {
+
+	if ctx.Err() != nil {
+		return nil, ErrTimeLimit
+	}
+
+	invite, err := api.MessagesCheckChatInvite(ctx, hash)
+	switch {
+	case tg.IsInviteHashExpired(err), tg.IsInviteHashInvalid(err):
+		return nil, ErrInviteInvalid
+	case err != nil:
+		return nil, apiError(err)
+	}
+
+	return invite, nil
+}
+
+func (t *telegramUserClient) inviteChannel(ctx context.Context, chat tg.ChatClass) (*tg.Channel, error) {
+
+	channel, ok := chat.(*tg.Channel)
+	if !ok {
+		return nil, ErrNotChannel
+	}
+
+	t.saveChannel(ctx, channel)
+
+	return channel, nil
+}
@@ -0,0 +1,195 @@
+package user
+
+import (
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"post-analyzer/internal/domain/entity"
+
+	"github.com/gotd/td/tg"
+)
+
+// toPost converts a channel or supergroup message into a domain post. Chats
+// and users returned along with the message name forward sources and the
+// authors of supergroup messages.
+func toPost(channelID int64, msg *tg.Message, chats []tg.ChatClass, users []tg.UserClass) *entity.Post {
+
+	post := &entity.Post{
+		ChannelID: channelID,
+		ID:        int64(msg.ID),
+		GroupedID: msg.GroupedID,
+		TopicID:   topicID(msg),
+		Date:      time.Unix(int64(msg.Date), 0),
+		URLs:      entityURLs(msg.Message, msg.Entities),
+		Views:     msg.Views,
+		Forwards:  msg.Forwards,
+	}
+
+	if reactions, ok := msg.GetReactions(); ok {
+		for _, result := range reactions.Results {
+			post.Reactions += result.Count
+		}
+	}
+
+	if editDate, ok := msg.GetEditDate(); ok {
+		post.EditDate = time.Unix(int64(editDate), 0)
+	}
+
+	// a message with media carries its caption in the text field
+	if msg.Media != nil {
+		post.Caption = msg.Message
+	} else {
+		post.Text = msg.Message
+	}
+
+	switch media := msg.Media.(type) {
+
+	case *tg.MessageMediaPoll:
+		poll := &entity.Poll{
+			Question: media.Poll.Question.Text,
+		}
+		for _, answer := range media.Poll.Answers {
+			poll.Options = append(poll.Options, answer.Text.Text)
+		}
+		post.Poll = poll
+
+	case *tg.MessageMediaWebPage:
+		if page, ok := media.Webpage.(*tg.WebPage); ok && page.URL != "" {
+			post.URLs = appendURL(post.URLs, page.URL)
+		}
+	}
+
+	if header, ok := msg.GetFwdFrom(); ok {
+		post.ForwardedFrom = forwardSource(header, chats)
+	}
+
+	// broadcast channels speak with one voice, only group members are named
+	if isMegagroup(channelID, chats) {
+		if from, ok := msg.GetFromID(); ok {
+			post.Author, post.FromBot = author(from, chats, users)
+		}
+	}
+
+	return post
+}
+
+// topicID returns the forum topic of the message, zero for the general
+// topic and for chats without topics.
+func topicID(msg *tg.Message) int {
+
+	header, ok := msg.ReplyTo.(*tg.MessageReplyHeader)
+	if !ok || !header.ForumTopic {
+		return 0
+	}
+
+	// replies inside a topic point to the topic in ReplyToTopID, the
+	// first level of messages points to it directly
+	if topID, ok := header.GetReplyToTopID(); ok {
+		return topID
+	}
+	return header.ReplyToMsgID
+}
+
+func isMegagroup(channelID int64, chats []tg.ChatClass) bool {
+
+	for _, chat := range chats {
+		if channel, ok := chat.(*tg.Channel); ok && channel.ID == channelID {
+			return channel.Megagroup
+		}
+	}
+
+	return false
+}
+
+// author names the sender of a supergroup message, which is a user or
+// a channel writing on behalf of itself.
+func author(from tg.PeerClass, chats []tg.ChatClass, users []tg.UserClass) (string, bool) {
+
+	switch peer := from.(type) {
+
+	case *tg.PeerUser:
+		for _, u := range users {
+			if user, ok := u.(*tg.User); ok && user.ID == peer.UserID {
+				return userName(user), user.Bot
+			}
+		}
+
+	case *tg.PeerChannel:
+		for _, chat := range chats {
+			if channel, ok := chat.(*tg.Channel); ok && channel.ID == peer.ChannelID {
+				return entity.ChannelLabel(channel.Username, channel.Title), false
+			}
+		}
+	}
+
+	return "", false
+}
+
+func userName(user *tg.User) string {
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	switch {
+	case user.Username == "":
+		return name
+	case name == "":
+		return "@" + user.Username
+	}
+	return name + " (@" + user.Username + ")"
+}
+
+func forwardSource(header tg.MessageFwdHeader, chats []tg.ChatClass) string {
+
+	if header.FromName != "" {
+		return header.FromName
+	}
+
+	if from, ok := header.GetFromID(); ok {
+		if peer, ok := from.(*tg.PeerChannel); ok {
+			for _, chat := range chats {
+				if channel, ok := chat.(*tg.Channel); ok && channel.ID == peer.ChannelID {
+					return entity.ChannelLabel(channel.Username, channel.Title)
+				}
+			}
+		}
+	}
+
+	return header.PostAuthor
+}
+
+// entityURLs collects plain and text links. Entity offsets are counted
+// in UTF-16 code units.
+func entityURLs(text string, entities []tg.MessageEntityClass) []string {
+
+	var urls []string
+	var encoded []uint16
+
+	for _, e := range entities {
+		switch e := e.(type) {
+
+		case *tg.MessageEntityTextURL:
+			urls = appendURL(urls, e.URL)
+
+		case *tg.MessageEntityURL:
+			if encoded == nil {
+				encoded = utf16.Encode([]rune(text))
+			}
+			if e.Offset >= 0 && e.Offset+e.Length <= len(encoded) {
+				urls = appendURL(urls, string(utf16.Decode(encoded[e.Offset:e.Offset+e.Length])))
+			}
+		}
+	}
+
+	return urls
+}
+
+func appendURL(urls []string, url string) []string {
+
+	for _, existing := range urls {
+		if existing == url {
+			return urls
+		}
+	}
+
+	return append(urls, url)
+}
@@ -0,0 +1,151 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/bin"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+)
+
+const (
+	defaultRequestsPerSecond = 5
+	defaultMaxFloodWait      = time.Minute
+	defaultMaxRetries        = 3
+	retryBackoff             = time.Second
+)
+
+// RateLimits bounds the request rate shared by all jobs and how long
+// a single call may wait out FLOOD_WAIT before giving up.
+type RateLimits struct {
+	RequestsPerSecond float64
+	MaxFloodWait      time.Duration
+	MaxRetries        int
+}
+
+// FloodWaitError is returned when Telegram asks to wait longer than the
+// caller can afford, so the caller can retry after Wait instead of dropping.
+type FloodWaitError struct {
+	Wait time.Duration
+}
+
+func (e *FloodWaitError) Error() string {
+	return fmt.Sprintf("telegram flood wait for %s", e.Wait)
+}
+
+// rateLimiter spaces out requests evenly and blocks methods that hit
+// FLOOD_WAIT until Telegram allows them again.
+type rateLimiter struct {
+	limits   RateLimits
+	interval time.Duration
+
+	mu          sync.Mutex
+	next        time.Time
+	floodedTill map[uint32]time.Time
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+
+	if limits.RequestsPerSecond <= 0 {
+		limits.RequestsPerSecond = defaultRequestsPerSecond
+	}
+	if limits.MaxFloodWait <= 0 {
+		limits.MaxFloodWait = defaultMaxFloodWait
+	}
+	if limits.MaxRetries <= 0 {
+		limits.MaxRetries = defaultMaxRetries
+	}
+
+	return &rateLimiter{
+		limits:      limits,
+		interval:    time.Duration(float64(time.Second) / limits.RequestsPerSecond),
+		floodedTill: make(map[uint32]time.Time),
+	}
+}
+
+func (r *rateLimiter) Handle(next tg.Invoker) telegram.InvokeFunc {
+
+	return func(ctx context.Context, input bin.Encoder, output bin.Decoder) error {
+
+		method := methodID(input)
+
+		for attempt := 0; ; attempt++ {
+
+			if err := r.wait(ctx, method); err != nil {
+				return err
+			}
+
+			err := next.Invoke(ctx, input, output)
+
+			wait, ok := tgerr.AsFloodWait(err)
+			if !ok {
+				return err
+			}
+
+			if wait > r.limits.MaxFloodWait || attempt >= r.limits.MaxRetries {
+				r.flood(method, wait)
+				return &FloodWaitError{Wait: wait}
+			}
+
+			log.Printf("telegram flood wait for %s, retry %d", wait, attempt+1)
+			r.flood(method, wait+retryBackoff<<attempt)
+		}
+	}
+}
+
+// wait reserves the next request slot. A slot beyond the context deadline
+// is reported as a flood wait, so scheduled jobs can be delayed.
+func (r *rateLimiter) wait(ctx context.Context, method uint32) error {
+
+	r.mu.Lock()
+	now := time.Now()
+	slot := now
+	if r.next.After(slot) {
+		slot = r.next
+	}
+	if till := r.floodedTill[method]; till.After(slot) {
+		slot = till
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && slot.After(deadline) {
+		r.mu.Unlock()
+		return &FloodWaitError{Wait: slot.Sub(now)}
+	}
+
+	r.next = slot.Add(r.interval)
+	r.mu.Unlock()
+
+	timer := time.NewTimer(slot.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ErrTimeLimit
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r *rateLimiter) flood(method uint32, wait time.Duration) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if till := time.Now().Add(wait); till.After(r.floodedTill[method]) {
+		r.floodedTill[method] = till
+	}
+}
+
+func methodID(input bin.Encoder) uint32 {
+
+	if typed, ok := input.(interface{ TypeID() uint32 }); ok {
+		return typed.TypeID()
+	}
+
+	return 0
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	botclient "post-analyzer/internal/adapters/telegram/bot"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/infrastructure/repository"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// AuthController asks the admin chat for Telegram login codes and QR scans,
+// so the user client can be authorized without a terminal.
+type AuthController struct {
+	client      *botclient.TelegramBotClient
+	settings    repository.SettingsRepository
+	adminChatID int64
+
+	mu      sync.Mutex
+	pending chan string
+}
+
+func NewAuthController(client *botclient.TelegramBotClient, settings repository.SettingsRepository, adminChatID int64) *AuthController {
+	return &AuthController{
+		client:      client,
+		settings:    settings,
+		adminChatID: adminChatID,
+	}
+}
+
+// language is the language set for the admin chat, prompts fall back to
+// the default one when the settings cannot be read.
+func (ac *AuthController) language(ctx context.Context) entity.Language {
+
+	settings, err := ac.settings.GetSettings(ctx, ac.adminChatID)
+	if err != nil {
+		log.Println(err)
+		return entity.DefaultLanguage
+	}
+
+	return settings.OutputLanguage()
+}
+
+func (ac *AuthController) RequestCode(ctx context.Context) (string, error) {
+
+	codes := make(chan string, 1)
+
+	ac.mu.Lock()
+	ac.pending = codes
+	ac.mu.Unlock()
+
+	defer func() {
+		ac.mu.Lock()
+		ac.pending = nil
+		ac.mu.Unlock()
+	}()
+
+	// Telegram expires codes that are forwarded as is,
+	// so the admin is asked to split the digits
+	prompt := presenter.Text(ac.language(ctx), presenter.MsgAuthCode)
+
+	if _, err := ac.client.SendTextMessage(ctx, ac.adminChatID, prompt); err != nil {
+		return "", err
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case code := <-codes:
+		return code, nil
+	}
+}
+
+func (ac *AuthController) ShowQR(ctx context.Context, image []byte, expires time.Time) error {
+
+	caption := presenter.Text(ac.language(ctx), presenter.MsgAuthQR, expires.Format("15:04:05"))
+
+	_, err := ac.client.SendPhoto(ctx, ac.adminChatID, image, caption)
+	return err
+}
+
+func (ac *AuthController) LoggedIn(ctx context.Context) error {
+
+	_, err := ac.client.SendTextMessage(ctx, ac.adminChatID, presenter.Text(ac.language(ctx), presenter.MsgAuthDone))
+	return err
+}
+
+func (ac *AuthController) AuthCodeMatch(update *models.Update) bool {
+
+	if update.Message == nil || update.Message.Chat.ID != ac.adminChatID || strings.HasPrefix(update.Message.Text, "/") {
+		return false
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	return ac.pending != nil
+}
+
+func (ac *AuthController) AuthCodeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	code := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, update.Message.Text)
+
+	language := ac.language(ctx)
+
+	reply := presenter.Text(language, presenter.MsgAuthCodeAccepted)
+	if code == "" {
+		reply = presenter.Text(language, presenter.MsgAuthCodeInvalid)
+	} else {
+		ac.mu.Lock()
+		if ac.pending != nil {
+			select {
+			case ac.pending <- code:
+			default:
+			}
+		}
+		ac.mu.Unlock()
+	}
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text:   reply,
+	})
+	if err != nil {
+		log.Printf("AuthCodeHandler: Failed to send message to chat: %v", err)
+	}
+}
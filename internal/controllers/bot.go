@@ -3,10 +3,13 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
 	"post-analyzer/internal/domain/presenter"
 	"post-analyzer/internal/usecase"
 
@@ -14,12 +17,23 @@ import (
 	"github.com/go-telegram/bot/models"
 )
 
+const (
+	listCallbackPrefix   = "list:"
+	resumeCallbackPrefix = "resume:"
+	resumeCatchUp        = "catchup"
+	resumeFromNow        = "now"
+)
+
 type BotController struct {
-	uc usecase.UseCase
+	uc     usecase.UseCase
+	wizard *wizardStore
 }
 
 func NewBotController(uc usecase.UseCase) *BotController {
-	return &BotController{uc: uc}
+	return &BotController{
+		uc:     uc,
+		wizard: newWizardStore(),
+	}
 }
 
 func (bc BotController) Reply(ctx context.Context, b *bot.Bot, chatID int64, text string) error {
@@ -31,9 +45,25 @@ func (bc BotController) Reply(ctx context.Context, b *bot.Bot, chatID int64, tex
 	return err
 }
 
+// language answers the chat in its own language, detecting it from the
+// sender's Telegram client on first contact.
+func (bc BotController) language(ctx context.Context, chatID int64, from *models.User) entity.Language {
+
+	lr := &dto.LanguageRequest{
+		ChatID: chatID,
+	}
+	if from != nil {
+		lr.ClientLanguage = from.LanguageCode
+	}
+
+	return bc.uc.ChatLanguage(ctx, lr)
+}
+
 func (bc BotController) StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
-	greetings := "Привет! Бот готов к работе!"
+	language := bc.language(ctx, update.Message.Chat.ID, update.Message.From)
+
+	greetings := presenter.Text(language, presenter.MsgGreeting)
 
 	err := bc.Reply(ctx, b, update.Message.From.ID, greetings)
 	if err != nil {
@@ -48,15 +78,24 @@ func (bc BotController) MonitorHandler(ctx context.Context, b *bot.Bot, update *
 		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/monitor")),
 	}
 
+	language := bc.language(ctx, mr.ChatID, update.Message.From)
+
+	key := newWizardKey(mr.ChatID, update.Message.From)
+	if mr.Message == "" {
+		bc.startMonitorWizard(ctx, b, key, language)
+		return
+	}
+	bc.wizard.reset(key)
+
 	err := bc.uc.MonitorChannel(ctx, mr)
 
 	if err != nil {
 
-		failMessage := "Канал не был добавлен в систему мониторинга!\n"
+		failMessage := presenter.Text(language, presenter.MsgMonitorFailed)
 
 		var presentedError *presenter.PresentedError
 		if errors.As(err, &presentedError) {
-			failMessage += err.Error()
+			failMessage += presentedError.Text(language)
 		}
 
 		err := bc.Reply(ctx, b, mr.ChatID, failMessage)
@@ -67,10 +106,549 @@ func (bc BotController) MonitorHandler(ctx context.Context, b *bot.Bot, update *
 		return
 	}
 
-	successMessage := "Успех! Канал успешно добавлен в систему мониторинга!"
+	successMessage := presenter.Text(language, presenter.MsgMonitorDone)
 
 	err = bc.Reply(ctx, b, mr.ChatID, successMessage)
 	if err != nil {
 		log.Printf("MonitorHandler: Failed to send message to chat: %v", err)
 	}
 }
+
+func (bc BotController) UnsubscribeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	ur := &dto.UnsubscribeRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/unsubscribe")),
+	}
+
+	language := bc.language(ctx, ur.ChatID, update.Message.From)
+
+	err := bc.uc.Unsubscribe(ctx, ur)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgUnsubscribeFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, ur.ChatID, failMessage)
+		if err != nil {
+			log.Printf("UnsubscribeHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgUnsubscribeDone)
+
+	err = bc.Reply(ctx, b, ur.ChatID, successMessage)
+	if err != nil {
+		log.Printf("UnsubscribeHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) EditHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	er := &dto.EditRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/edit")),
+	}
+
+	language := bc.language(ctx, er.ChatID, update.Message.From)
+
+	err := bc.uc.EditSubscription(ctx, er)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgEditFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, er.ChatID, failMessage)
+		if err != nil {
+			log.Printf("EditHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgEditDone)
+
+	err = bc.Reply(ctx, b, er.ChatID, successMessage)
+	if err != nil {
+		log.Printf("EditHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) DigestHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	dr := &dto.DigestRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/digest")),
+	}
+
+	language := bc.language(ctx, dr.ChatID, update.Message.From)
+
+	err := bc.Reply(ctx, b, dr.ChatID, presenter.Text(language, presenter.MsgDigestPreparing))
+	if err != nil {
+		log.Printf("DigestHandler: Failed to send message to chat: %v", err)
+	}
+
+	if err := bc.uc.Digest(ctx, dr); err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgDigestFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, dr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("DigestHandler: Failed to send message to chat: %v", err)
+		}
+	}
+}
+
+func (bc BotController) ListHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	lr := &dto.ListRequest{
+		ChatID: update.Message.Chat.ID,
+	}
+
+	language := bc.language(ctx, lr.ChatID, update.Message.From)
+
+	text, markup := bc.renderSubscriptions(ctx, lr, language)
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      lr.ChatID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Printf("ListHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) ListPageHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	query := update.CallbackQuery
+
+	if _, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	}); err != nil {
+		log.Printf("ListPageHandler: Failed to answer callback query: %v", err)
+	}
+
+	message := query.Message.Message
+	if message == nil {
+		return
+	}
+
+	page, err := strconv.Atoi(strings.TrimPrefix(query.Data, listCallbackPrefix))
+	if err != nil {
+		return
+	}
+
+	lr := &dto.ListRequest{
+		ChatID: message.Chat.ID,
+		Page:   page,
+	}
+
+	language := bc.language(ctx, lr.ChatID, &query.From)
+
+	text, markup := bc.renderSubscriptions(ctx, lr, language)
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      lr.ChatID,
+		MessageID:   message.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Printf("ListPageHandler: Failed to edit message: %v", err)
+	}
+}
+
+func (bc BotController) renderSubscriptions(ctx context.Context, lr *dto.ListRequest, language entity.Language) (string, models.ReplyMarkup) {
+
+	page, err := bc.uc.ListSubscriptions(ctx, lr)
+	if err != nil {
+		failMessage := presenter.Text(language, presenter.MsgListFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		return failMessage, nil
+	}
+
+	if len(page.Subscriptions) == 0 {
+		return presenter.Text(language, presenter.MsgListEmpty), nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(presenter.Text(language, presenter.MsgListHeader, page.Page+1, page.TotalPages, page.Timezone))
+
+	for _, sub := range page.Subscriptions {
+
+		nextRun := "—"
+		if !sub.NextRun.IsZero() {
+			nextRun = sub.NextRun.Format("02.01.2006 15:04")
+		}
+
+		status := presenter.Text(language, presenter.MsgListActive)
+		if !sub.Active {
+			status = presenter.Text(language, presenter.MsgListPaused)
+		}
+
+		builder.WriteString(presenter.Text(language, presenter.MsgListItem,
+			sub.Channel, status, sub.Schedule, sub.LastCheckedPostID, nextRun))
+	}
+
+	if page.TotalPages == 1 {
+		return builder.String(), nil
+	}
+
+	var buttons []models.InlineKeyboardButton
+	if page.Page > 0 {
+		buttons = append(buttons, models.InlineKeyboardButton{
+			Text:         presenter.Text(language, presenter.MsgPageBack),
+			CallbackData: fmt.Sprintf("%s%d", listCallbackPrefix, page.Page-1),
+		})
+	}
+	if page.Page < page.TotalPages-1 {
+		buttons = append(buttons, models.InlineKeyboardButton{
+			Text:         presenter.Text(language, presenter.MsgPageForward),
+			CallbackData: fmt.Sprintf("%s%d", listCallbackPrefix, page.Page+1),
+		})
+	}
+
+	return builder.String(), &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{buttons},
+	}
+}
+
+func (bc BotController) TimezoneHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	tr := &dto.TimezoneRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/timezone")),
+	}
+
+	language := bc.language(ctx, tr.ChatID, update.Message.From)
+
+	err := bc.uc.SetTimezone(ctx, tr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgTimezoneFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, tr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("TimezoneHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgTimezoneDone)
+
+	err = bc.Reply(ctx, b, tr.ChatID, successMessage)
+	if err != nil {
+		log.Printf("TimezoneHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) LanguageHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	lr := &dto.LanguageRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/language")),
+	}
+
+	language := bc.language(ctx, lr.ChatID, update.Message.From)
+
+	selected, err := bc.uc.SetLanguage(ctx, lr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgLanguageFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, lr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("LanguageHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	// the confirmation already comes in the new language
+	successMessage := presenter.Text(selected, presenter.MsgLanguageDone)
+
+	err = bc.Reply(ctx, b, lr.ChatID, successMessage)
+	if err != nil {
+		log.Printf("LanguageHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) OptionsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	or := &dto.OptionsRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/options")),
+	}
+
+	language := bc.language(ctx, or.ChatID, update.Message.From)
+
+	options, err := bc.uc.SetReadOptions(ctx, or)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgOptionsFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, or.ChatID, failMessage)
+		if err != nil {
+			log.Printf("OptionsHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgOptionsDone, options)
+
+	err = bc.Reply(ctx, b, or.ChatID, successMessage)
+	if err != nil {
+		log.Printf("OptionsHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) ModelHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	mr := &dto.ModelRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/model")),
+	}
+
+	language := bc.language(ctx, mr.ChatID, update.Message.From)
+
+	options, err := bc.uc.SetModelOptions(ctx, mr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgModelFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, mr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("ModelHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgModelDone, options)
+
+	err = bc.Reply(ctx, b, mr.ChatID, successMessage)
+	if err != nil {
+		log.Printf("ModelHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) PromptHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	pr := &dto.PromptRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/prompt")),
+	}
+
+	language := bc.language(ctx, pr.ChatID, update.Message.From)
+
+	options, err := bc.uc.SetPrompt(ctx, pr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgPromptFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, pr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("PromptHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgPromptDone, options)
+
+	err = bc.Reply(ctx, b, pr.ChatID, successMessage)
+	if err != nil {
+		log.Printf("PromptHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) PauseHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	pr := &dto.PauseRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/pause")),
+	}
+
+	language := bc.language(ctx, pr.ChatID, update.Message.From)
+
+	err := bc.uc.Pause(ctx, pr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgPauseFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, pr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("PauseHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgPauseDone)
+
+	err = bc.Reply(ctx, b, pr.ChatID, successMessage)
+	if err != nil {
+		log.Printf("PauseHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) ResumeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	rr := &dto.ResumeRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/resume")),
+	}
+
+	language := bc.language(ctx, rr.ChatID, update.Message.From)
+
+	subscriptionID, err := bc.uc.CheckPaused(ctx, rr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgResumeFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, rr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("ResumeHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	// callback data is limited to 64 bytes, so the channel goes by a subscription ID
+	target := strconv.FormatInt(subscriptionID, 10)
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: rr.ChatID,
+		Text:   presenter.Text(language, presenter.MsgResumeQuestion),
+		ReplyMarkup: &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: presenter.Text(language, presenter.MsgResumeCatchUp), CallbackData: resumeCallbackPrefix + resumeCatchUp + ":" + target},
+				{Text: presenter.Text(language, presenter.MsgResumeFromNow), CallbackData: resumeCallbackPrefix + resumeFromNow + ":" + target},
+			}},
+		},
+	})
+	if err != nil {
+		log.Printf("ResumeHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) ResumeModeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	query := update.CallbackQuery
+
+	if _, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	}); err != nil {
+		log.Printf("ResumeModeHandler: Failed to answer callback query: %v", err)
+	}
+
+	message := query.Message.Message
+	if message == nil {
+		return
+	}
+
+	mode, target, found := strings.Cut(strings.TrimPrefix(query.Data, resumeCallbackPrefix), ":")
+	if !found {
+		return
+	}
+
+	subscriptionID, err := strconv.ParseInt(target, 10, 64)
+	if err != nil {
+		return
+	}
+
+	rr := &dto.ResumeRequest{
+		ChatID:         message.Chat.ID,
+		SubscriptionID: subscriptionID,
+		CatchUp:        mode == resumeCatchUp,
+	}
+
+	language := bc.language(ctx, rr.ChatID, &query.From)
+
+	text := presenter.Text(language, presenter.MsgResumeDone)
+	if err := bc.uc.Resume(ctx, rr); err != nil {
+
+		text = presenter.Text(language, presenter.MsgResumeFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			text += presentedError.Text(language)
+		}
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    rr.ChatID,
+		MessageID: message.ID,
+		Text:      text,
+	})
+	if err != nil {
+		log.Printf("ResumeModeHandler: Failed to edit message: %v", err)
+	}
+}
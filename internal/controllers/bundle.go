@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/presenter"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func (bc BotController) BundleHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	br := &dto.BundleRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/bundle")),
+	}
+
+	language := bc.language(ctx, br.ChatID, update.Message.From)
+
+	err := bc.uc.CreateBundle(ctx, br)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgBundleFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, br.ChatID, failMessage)
+		if err != nil {
+			log.Printf("BundleHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgBundleDone)
+
+	err = bc.Reply(ctx, b, br.ChatID, successMessage)
+	if err != nil {
+		log.Printf("BundleHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) UnbundleHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	br := &dto.BundleRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/unbundle")),
+	}
+
+	language := bc.language(ctx, br.ChatID, update.Message.From)
+
+	err := bc.uc.DeleteBundle(ctx, br)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgUnbundleFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, br.ChatID, failMessage)
+		if err != nil {
+			log.Printf("UnbundleHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	successMessage := presenter.Text(language, presenter.MsgUnbundleDone)
+
+	err = bc.Reply(ctx, b, br.ChatID, successMessage)
+	if err != nil {
+		log.Printf("UnbundleHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) BundlesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	br := &dto.BundleRequest{
+		ChatID: update.Message.Chat.ID,
+	}
+
+	language := bc.language(ctx, br.ChatID, update.Message.From)
+
+	text := presenter.Text(language, presenter.MsgBundlesEmpty)
+
+	bundles, err := bc.uc.ListBundles(ctx, br)
+	if err != nil {
+
+		text = presenter.Text(language, presenter.MsgBundlesFailed)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			text += presentedError.Text(language)
+		}
+
+	} else if len(bundles) > 0 {
+
+		var builder strings.Builder
+		builder.WriteString(presenter.Text(language, presenter.MsgBundlesHeader))
+
+		for _, bundle := range bundles {
+
+			nextRun := "—"
+			if !bundle.NextRun.IsZero() {
+				nextRun = bundle.NextRun.Format("02.01.2006 15:04")
+			}
+
+			builder.WriteString(presenter.Text(language, presenter.MsgBundlesItem,
+				bundle.Name, strings.Join(bundle.Channels, ", "), bundle.Schedule, nextRun))
+		}
+
+		text = builder.String()
+	}
+
+	err = bc.Reply(ctx, b, br.ChatID, text)
+	if err != nil {
+		log.Printf("BundlesHandler: Failed to send message to chat: %v", err)
+	}
+}
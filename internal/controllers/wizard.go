@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	monitorCallbackPrefix = "monitor:"
+	monitorCancelData     = monitorCallbackPrefix + "cancel"
+
+	// an abandoned wizard stops catching the chat's messages after a while
+	wizardTimeout = 10 * time.Minute
+)
+
+var wizardTimes = [][]string{
+	{"07:00", "08:00", "09:00"},
+	{"12:00", "18:00", "21:00"},
+}
+
+type wizardStep int
+
+const (
+	stepAwaitingChannel wizardStep = iota
+	stepAwaitingTime
+)
+
+type wizardState struct {
+	step    wizardStep
+	channel string
+	expires time.Time
+}
+
+// wizardKey ties a wizard to the member who started it, so other messages
+// in a group chat are not taken for their answers.
+type wizardKey struct {
+	chatID int64
+	userID int64
+}
+
+type wizardStore struct {
+	mu     sync.Mutex
+	states map[wizardKey]*wizardState
+}
+
+func newWizardStore() *wizardStore {
+	return &wizardStore{
+		states: make(map[wizardKey]*wizardState),
+	}
+}
+
+func (w *wizardStore) get(key wizardKey) (wizardState, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.states[key]
+	if !ok {
+		return wizardState{}, false
+	}
+	if time.Now().After(state.expires) {
+		delete(w.states, key)
+		return wizardState{}, false
+	}
+	return *state, true
+}
+
+func (w *wizardStore) set(key wizardKey, state wizardState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for k, s := range w.states {
+		if now.After(s.expires) {
+			delete(w.states, k)
+		}
+	}
+
+	state.expires = now.Add(wizardTimeout)
+	w.states[key] = &state
+}
+
+func (w *wizardStore) reset(key wizardKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.states, key)
+}
+
+// newWizardKey keys the wizard by chat and sender, messages without a
+// sender share one wizard per chat.
+func newWizardKey(chatID int64, from *models.User) wizardKey {
+
+	key := wizardKey{chatID: chatID}
+	if from != nil {
+		key.userID = from.ID
+	}
+	return key
+}
+
+func (bc BotController) startMonitorWizard(ctx context.Context, b *bot.Bot, key wizardKey, language entity.Language) {
+
+	chatID := key.chatID
+	bc.wizard.set(key, wizardState{step: stepAwaitingChannel})
+
+	err := bc.Reply(ctx, b, chatID, presenter.Text(language, presenter.MsgWizardChannel))
+	if err != nil {
+		log.Printf("MonitorHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) WizardMatch(update *models.Update) bool {
+
+	if update.Message == nil || update.Message.Text == "" || strings.HasPrefix(update.Message.Text, "/") {
+		return false
+	}
+
+	state, ok := bc.wizard.get(newWizardKey(update.Message.Chat.ID, update.Message.From))
+	return ok && state.step == stepAwaitingChannel
+}
+
+func (bc BotController) WizardChannelHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	cr := &dto.ChannelRequest{
+		ChatID:  update.Message.Chat.ID,
+		Message: strings.TrimSpace(update.Message.Text),
+	}
+
+	language := bc.language(ctx, cr.ChatID, update.Message.From)
+
+	label, err := bc.uc.CheckChannel(ctx, cr)
+
+	if err != nil {
+
+		failMessage := presenter.Text(language, presenter.MsgWizardNotFound)
+
+		var presentedError *presenter.PresentedError
+		if errors.As(err, &presentedError) {
+			failMessage += presentedError.Text(language)
+		}
+
+		err := bc.Reply(ctx, b, cr.ChatID, failMessage)
+		if err != nil {
+			log.Printf("WizardChannelHandler: Failed to send message to chat: %v", err)
+		}
+
+		return
+	}
+
+	bc.wizard.set(newWizardKey(cr.ChatID, update.Message.From), wizardState{step: stepAwaitingTime, channel: cr.Message})
+
+	var keyboard [][]models.InlineKeyboardButton
+	for _, row := range wizardTimes {
+
+		var buttons []models.InlineKeyboardButton
+		for _, t := range row {
+			buttons = append(buttons, models.InlineKeyboardButton{
+				Text:         t,
+				CallbackData: monitorCallbackPrefix + t,
+			})
+		}
+
+		keyboard = append(keyboard, buttons)
+	}
+	keyboard = append(keyboard, []models.InlineKeyboardButton{{
+		Text:         presenter.Text(language, presenter.MsgWizardCancel),
+		CallbackData: monitorCancelData,
+	}})
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      cr.ChatID,
+		Text:        presenter.Text(language, presenter.MsgWizardFound, label),
+		ReplyMarkup: &models.InlineKeyboardMarkup{InlineKeyboard: keyboard},
+	})
+	if err != nil {
+		log.Printf("WizardChannelHandler: Failed to send message to chat: %v", err)
+	}
+}
+
+func (bc BotController) WizardTimeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+	query := update.CallbackQuery
+
+	if _, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	}); err != nil {
+		log.Printf("WizardTimeHandler: Failed to answer callback query: %v", err)
+	}
+
+	message := query.Message.Message
+	if message == nil {
+		return
+	}
+	chatID := message.Chat.ID
+
+	// buttons pressed by other members or after the wizard expired do nothing
+	key := newWizardKey(chatID, &query.From)
+	state, ok := bc.wizard.get(key)
+	if !ok || state.step != stepAwaitingTime {
+		return
+	}
+	bc.wizard.reset(key)
+
+	language := bc.language(ctx, chatID, &query.From)
+
+	text := presenter.Text(language, presenter.MsgWizardCancelled)
+	if query.Data != monitorCancelData {
+
+		mr := &dto.MonitorRequest{
+			ChatID:  chatID,
+			Message: state.channel + " " + strings.TrimPrefix(query.Data, monitorCallbackPrefix),
+		}
+
+		text = presenter.Text(language, presenter.MsgMonitorDone)
+		if err := bc.uc.MonitorChannel(ctx, mr); err != nil {
+
+			text = presenter.Text(language, presenter.MsgMonitorFailed)
+
+			var presentedError *presenter.PresentedError
+			if errors.As(err, &presentedError) {
+				text += presentedError.Text(language)
+			}
+		}
+	}
+
+	_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: message.ID,
+		Text:      text,
+	})
+	if err != nil {
+		log.Printf("WizardTimeHandler: Failed to edit message: %v", err)
+	}
+}
@@ -1,6 +1,98 @@
 package dto
 
+import "time"
+
 type MonitorRequest struct {
 	ChatID  int64
 	Message string
 }
+
+type ChannelRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type UnsubscribeRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type EditRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type DigestRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type ListRequest struct {
+	ChatID int64
+	Page   int
+}
+
+type SubscriptionInfo struct {
+	Channel           string
+	Schedule          string
+	LastCheckedPostID int64
+	Active            bool
+	NextRun           time.Time
+}
+
+type SubscriptionsPage struct {
+	Subscriptions []SubscriptionInfo
+	Timezone      string
+	Page          int
+	TotalPages    int
+}
+
+type TimezoneRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type PauseRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type ResumeRequest struct {
+	ChatID         int64
+	Message        string
+	SubscriptionID int64
+	CatchUp        bool
+}
+
+type OptionsRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type ModelRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type PromptRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type LanguageRequest struct {
+	ChatID         int64
+	Message        string
+	ClientLanguage string
+}
+
+type BundleRequest struct {
+	ChatID  int64
+	Message string
+}
+
+type BundleInfo struct {
+	Name     string
+	Channels []string
+	Schedule string
+	NextRun  time.Time
+}
@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxPromptLength = 4000
+	MaxTemperature  = 2
+
+	defaultValue = "default"
+)
+
+var (
+	ErrUnknownModelOption = errors.New("unknown model option")
+	ErrModelOptionValue   = errors.New("invalid model option value")
+	ErrPromptLength       = errors.New("invalid prompt length")
+)
+
+// AnalysisOptions override how digests are written. Unset fields fall back
+// to the chat defaults and then to the defaults of the analysis service.
+type AnalysisOptions struct {
+	Model       string
+	Temperature *float32
+	TopP        *float32
+	Prompt      string
+}
+
+// Or fills the unset fields from defaults.
+func (o AnalysisOptions) Or(defaults AnalysisOptions) AnalysisOptions {
+
+	if o.Model == "" {
+		o.Model = defaults.Model
+	}
+	if o.Temperature == nil {
+		o.Temperature = defaults.Temperature
+	}
+	if o.TopP == nil {
+		o.TopP = defaults.TopP
+	}
+	if o.Prompt == "" {
+		o.Prompt = defaults.Prompt
+	}
+
+	return o
+}
+
+// Set applies a "key=value" option: model=ID, temperature=0..2, top_p=0..1,
+// any of them set to "default" falls back again.
+func (o *AnalysisOptions) Set(option string) error {
+
+	key, value, found := strings.Cut(option, "=")
+	if !found || value == "" {
+		return fmt.Errorf("%w: %s", ErrModelOptionValue, option)
+	}
+
+	key = strings.ToLower(key)
+	reset := strings.EqualFold(value, defaultValue)
+
+	switch key {
+
+	case "model":
+		if reset {
+			o.Model = ""
+			return nil
+		}
+		o.Model = value
+
+	case "temperature":
+		if reset {
+			o.Temperature = nil
+			return nil
+		}
+
+		temperature, err := strconv.ParseFloat(value, 32)
+		if err != nil || temperature < 0 || temperature > MaxTemperature {
+			return fmt.Errorf("%w: %s", ErrModelOptionValue, option)
+		}
+		o.Temperature = float32Ptr(temperature)
+
+	case "top_p":
+		if reset {
+			o.TopP = nil
+			return nil
+		}
+
+		topP, err := strconv.ParseFloat(value, 32)
+		if err != nil || topP <= 0 || topP > 1 {
+			return fmt.Errorf("%w: %s", ErrModelOptionValue, option)
+		}
+		o.TopP = float32Ptr(topP)
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownModelOption, key)
+	}
+
+	return nil
+}
+
+// SetPrompt replaces the system prompt, "default" restores the built-in one.
+func (o *AnalysisOptions) SetPrompt(prompt string) error {
+
+	prompt = strings.TrimSpace(prompt)
+	if strings.EqualFold(prompt, defaultValue) {
+		o.Prompt = ""
+		return nil
+	}
+
+	if prompt == "" || utf8.RuneCountInString(prompt) > MaxPromptLength {
+		return ErrPromptLength
+	}
+
+	o.Prompt = prompt
+	return nil
+}
+
+func (o AnalysisOptions) String() string {
+
+	model := defaultValue
+	if o.Model != "" {
+		model = o.Model
+	}
+
+	prompt := defaultValue
+	if o.Prompt != "" {
+		prompt = "custom"
+	}
+
+	return fmt.Sprintf("model=%s temperature=%s top_p=%s prompt=%s", model, floatName(o.Temperature), floatName(o.TopP), prompt)
+}
+
+func float32Ptr(value float64) *float32 {
+	v := float32(value)
+	return &v
+}
+
+func floatName(value *float32) string {
+
+	if value == nil {
+		return defaultValue
+	}
+	return strconv.FormatFloat(float64(*value), 'g', -1, 32)
+}
@@ -0,0 +1,18 @@
+package entity
+
+type Bundle struct {
+	ID         int64
+	ChatID     int64
+	Name       string
+	Channels   []*BundleChannel
+	Schedule   Schedule
+	Timezone   string
+	ScheduleID int
+}
+
+type BundleChannel struct {
+	ChannelID         int64
+	ChannelUsername   string
+	ChannelTitle      string
+	LastCheckedPostID int64
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// Channel is a resolved Telegram channel peer or a web feed. AccessHash is
+// zero until the user client has resolved the channel at least once,
+// Username is empty for private channels joined by invite link and for
+// feeds. About, Participants and LinkedChatID come from the full channel
+// info, InfoUpdatedAt is zero until it has been fetched.
+type Channel struct {
+	ID         int64
+	AccessHash int64
+	Username   string
+	Title      string
+	FeedURL    string
+
+	About         string
+	Participants  int
+	LinkedChatID  int64
+	InfoUpdatedAt time.Time
+}
+
+// FeedChannelID derives a stable channel ID from a feed URL. Feed IDs are
+// negative, so they never collide with Telegram channel IDs.
+func FeedChannelID(url string) int64 {
+
+	h := fnv.New64a()
+	h.Write([]byte(url))
+
+	return -int64(h.Sum64()>>1) - 1
+}
+
+func IsFeedChannel(channelID int64) bool {
+	return channelID < 0
+}
+
+// ChannelLabel names a channel for chat messages.
+func ChannelLabel(username, title string) string {
+
+	if username != "" {
+		return "@" + username
+	}
+	return title
+}
+
+// ChannelName names a channel for listings, by title when it is known.
+func ChannelName(username, title string) string {
+
+	switch {
+	case title == "":
+		return ChannelLabel(username, title)
+	case username == "":
+		return title
+	}
+	return title + " (@" + username + ")"
+}
+
+// PostLink points to a channel post, private channels are linked by ID
+// and open only for their members.
+func PostLink(username string, channelID, postID int64) string {
+
+	if username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", username, postID)
+	}
+	return fmt.Sprintf("https://t.me/c/%d/%d", channelID, postID)
+}
@@ -0,0 +1,24 @@
+package entity
+
+// ChannelHistory holds posts from newest to oldest. Fetched counts every
+// message received from the source. Limited is set when the fetch limit
+// cut the read short, Truncated then estimates how many newer-than-requested
+// messages were left out, or stays zero when the source cannot tell.
+// Position is where the next read continues from; sources numbering posts
+// in order leave it zero and continue after the newest post.
+type ChannelHistory struct {
+	Posts     []*Post
+	Fetched   int
+	Limited   bool
+	Truncated int
+	Position  int64
+}
+
+// NextReadID is the read position to store once the posts are handled.
+func (h *ChannelHistory) NextReadID() int64 {
+
+	if h.Position != 0 {
+		return h.Position
+	}
+	return h.Posts[0].ID
+}
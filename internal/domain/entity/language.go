@@ -0,0 +1,32 @@
+package entity
+
+import "strings"
+
+// Language is the language a chat is answered in and its digests are
+// written in, named by its ISO 639-1 code.
+type Language string
+
+const (
+	LanguageRussian Language = "ru"
+	LanguageEnglish Language = "en"
+	LanguageGerman  Language = "de"
+
+	DefaultLanguage = LanguageRussian
+)
+
+var Languages = []Language{LanguageRussian, LanguageEnglish, LanguageGerman}
+
+// ParseLanguage accepts a supported language code, with or without a region
+// as Telegram reports it, e.g. "en-US".
+func ParseLanguage(code string) (Language, bool) {
+
+	code, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+
+	for _, language := range Languages {
+		if string(language) == code {
+			return language, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GeneralTopicID is the default topic of a forum, its messages carry no
+// topic reference.
+const GeneralTopicID = 1
+
+var (
+	ErrUnknownOption = errors.New("unknown option")
+	ErrOptionValue   = errors.New("invalid option value")
+)
+
+// ReadOptions narrow down what a subscription reads from a supergroup or
+// a channel with discussions. Service messages are never read.
+type ReadOptions struct {
+	TopicID         int
+	ExcludeBots     bool
+	IncludeComments bool
+}
+
+// Accepts reports whether the post passes the topic and author filters.
+func (o ReadOptions) Accepts(post *Post) bool {
+
+	if o.ExcludeBots && post.FromBot {
+		return false
+	}
+
+	if o.TopicID != 0 {
+		topic := post.TopicID
+		if topic == 0 {
+			topic = GeneralTopicID
+		}
+		return topic == o.TopicID
+	}
+
+	return true
+}
+
+// Filter keeps the posts the options accept, in their original order.
+func (o ReadOptions) Filter(posts []*Post) []*Post {
+
+	accepted := make([]*Post, 0, len(posts))
+	for _, post := range posts {
+		if o.Accepts(post) {
+			accepted = append(accepted, post)
+		}
+	}
+
+	return accepted
+}
+
+// Set applies a "key=value" option: topic=ID|all, bots=on|off, comments=on|off.
+func (o *ReadOptions) Set(option string) error {
+
+	key, value, found := strings.Cut(strings.ToLower(option), "=")
+	if !found {
+		return fmt.Errorf("%w: %s", ErrOptionValue, option)
+	}
+
+	switch key {
+
+	case "topic":
+		if value == "all" {
+			o.TopicID = 0
+			return nil
+		}
+
+		topic, err := strconv.Atoi(value)
+		if err != nil || topic <= 0 {
+			return fmt.Errorf("%w: %s", ErrOptionValue, option)
+		}
+		o.TopicID = topic
+
+	case "bots":
+		enabled, err := switchValue(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, option)
+		}
+		o.ExcludeBots = !enabled
+
+	case "comments":
+		enabled, err := switchValue(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, option)
+		}
+		o.IncludeComments = enabled
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOption, key)
+	}
+
+	return nil
+}
+
+func (o ReadOptions) String() string {
+
+	topic := "all"
+	if o.TopicID != 0 {
+		topic = strconv.Itoa(o.TopicID)
+	}
+
+	return fmt.Sprintf("topic=%s bots=%s comments=%s", topic, switchName(!o.ExcludeBots), switchName(o.IncludeComments))
+}
+
+func switchValue(value string) (bool, error) {
+
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+
+	return false, ErrOptionValue
+}
+
+func switchName(enabled bool) string {
+
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const commentLength = 300
+
+// Post is a channel publication or a supergroup message. Text holds the
+// body of a text message, Caption the text attached to media, albums are
+// merged into one post carrying the ID of their newest part. Comments are
+// only loaded on demand from the linked discussion group.
+type Post struct {
+	ChannelID int64
+	ID        int64
+	GroupedID int64
+	TopicID   int
+	Date      time.Time
+	EditDate  time.Time
+	Link      string
+
+	Author  string
+	FromBot bool
+
+	Text          string
+	Caption       string
+	URLs          []string
+	ForwardedFrom string
+	Poll          *Poll
+	Comments      []*Post
+
+	Views     int
+	Forwards  int
+	Reactions int
+}
+
+type Poll struct {
+	Question string
+	Options  []string
+}
+
+func (p *Post) IsEmpty() bool {
+	return p.Text == "" && p.Caption == "" && p.Poll == nil && len(p.URLs) == 0
+}
+
+// Content renders the post with its comments as plain text for analysis.
+// Comments, forwards and polls are marked with symbols rather than words,
+// so the text reads the same whatever the chat language is.
+func (p *Post) Content() string {
+
+	content := p.render()
+	if len(p.Comments) == 0 {
+		return content
+	}
+
+	lines := []string{content, "💬"}
+	for _, comment := range p.Comments {
+		lines = append(lines, "— "+comment.Excerpt(commentLength))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *Post) render() string {
+
+	var parts []string
+
+	if p.Author != "" {
+		parts = append(parts, p.Author+":")
+	}
+	if p.ForwardedFrom != "" {
+		parts = append(parts, "↪ "+p.ForwardedFrom+":")
+	}
+	if p.Text != "" {
+		parts = append(parts, p.Text)
+	}
+	if p.Caption != "" {
+		parts = append(parts, p.Caption)
+	}
+	if p.Poll != nil {
+		parts = append(parts, "📊 "+p.Poll.Question+" ("+strings.Join(p.Poll.Options, " / ")+")")
+	}
+
+	// links already present in the text are not repeated
+	body := strings.Join(parts, "\n")
+	for _, url := range p.URLs {
+		if !strings.Contains(body, url) {
+			parts = append(parts, url)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// URL links to the post, Telegram posts have no Link of their own.
+func (p *Post) URL(username string) string {
+
+	if p.Link != "" {
+		return p.Link
+	}
+	return PostLink(username, p.ChannelID, p.ID)
+}
+
+// Excerpt cuts the post content to limit runes to name it in chat messages.
+func (p *Post) Excerpt(limit int) string {
+
+	content := []rune(strings.Join(strings.Fields(p.render()), " "))
+	if len(content) <= limit {
+		return string(content)
+	}
+
+	return strings.TrimSpace(string(content[:limit])) + "…"
+}
+
+// MergeAlbums joins consecutive parts of the same album. Posts are expected
+// from newest to oldest, as Telegram returns them.
+func MergeAlbums(posts []*Post) []*Post {
+
+	merged := make([]*Post, 0, len(posts))
+	for _, post := range posts {
+
+		if len(merged) > 0 && post.GroupedID != 0 {
+			if last := merged[len(merged)-1]; last.GroupedID == post.GroupedID {
+				last.merge(post)
+				continue
+			}
+		}
+
+		merged = append(merged, post)
+	}
+
+	return merged
+}
+
+// merge adds an older album part to the post.
+func (p *Post) merge(part *Post) {
+
+	p.Text = joinText(part.Text, p.Text)
+	p.Caption = joinText(part.Caption, p.Caption)
+
+	for _, url := range part.URLs {
+		if !slices.Contains(p.URLs, url) {
+			p.URLs = append(p.URLs, url)
+		}
+	}
+
+	if p.ForwardedFrom == "" {
+		p.ForwardedFrom = part.ForwardedFrom
+	}
+	if p.Poll == nil {
+		p.Poll = part.Poll
+	}
+	if part.EditDate.After(p.EditDate) {
+		p.EditDate = part.EditDate
+	}
+	if part.Date.Before(p.Date) {
+		p.Date = part.Date
+	}
+
+	p.Views = max(p.Views, part.Views)
+	p.Forwards = max(p.Forwards, part.Forwards)
+	p.Reactions = max(p.Reactions, part.Reactions)
+}
+
+func joinText(older, newer string) string {
+
+	switch {
+	case older == "":
+		return newer
+	case newer == "":
+		return older
+	}
+
+	return older + "\n" + newer
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMergeAlbums(t *testing.T) {
+
+	date := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		posts []*Post
+		want  []*Post
+	}{
+		{
+			name:  "no albums",
+			posts: []*Post{{ID: 2, Text: "b"}, {ID: 1, Text: "a"}},
+			want:  []*Post{{ID: 2, Text: "b"}, {ID: 1, Text: "a"}},
+		},
+		{
+			name: "album parts joined oldest first",
+			posts: []*Post{
+				{ID: 3, GroupedID: 7, Caption: "second", URLs: []string{"u2"}, Views: 10, Date: date.Add(time.Second)},
+				{ID: 2, GroupedID: 7, Caption: "first", URLs: []string{"u1", "u2"}, Views: 12, Date: date},
+				{ID: 1, Text: "single"},
+			},
+			want: []*Post{
+				{ID: 3, GroupedID: 7, Caption: "first\nsecond", URLs: []string{"u2", "u1"}, Views: 12, Date: date},
+				{ID: 1, Text: "single"},
+			},
+		},
+		{
+			name: "empty parts keep the text",
+			posts: []*Post{
+				{ID: 3, GroupedID: 7},
+				{ID: 2, GroupedID: 7, Caption: "caption", Forwards: 4},
+			},
+			want: []*Post{
+				{ID: 3, GroupedID: 7, Caption: "caption", Forwards: 4},
+			},
+		},
+		{
+			name: "different albums stay apart",
+			posts: []*Post{
+				{ID: 4, GroupedID: 8, Caption: "b"},
+				{ID: 3, GroupedID: 7, Caption: "a"},
+			},
+			want: []*Post{
+				{ID: 4, GroupedID: 8, Caption: "b"},
+				{ID: 3, GroupedID: 7, Caption: "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got := MergeAlbums(tt.posts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d posts, want %d", len(got), len(tt.want))
+			}
+
+			for i, post := range got {
+				want := tt.want[i]
+				if post.ID != want.ID || post.Text != want.Text || post.Caption != want.Caption ||
+					!slices.Equal(post.URLs, want.URLs) || post.Views != want.Views ||
+					post.Forwards != want.Forwards || !post.Date.Equal(want.Date) {
+					t.Errorf("post %d = %+v, want %+v", i, *post, *want)
+				}
+			}
+		})
+	}
+}
+
+func TestPostContent(t *testing.T) {
+
+	tests := []struct {
+		name string
+		post Post
+		want string
+	}{
+		{
+			name: "text with a new link",
+			post: Post{Text: "news", URLs: []string{"https://example.com"}},
+			want: "news\nhttps://example.com",
+		},
+		{
+			name: "link already in the text",
+			post: Post{Text: "see https://example.com", URLs: []string{"https://example.com"}},
+			want: "see https://example.com",
+		},
+		{
+			name: "author and forward",
+			post: Post{Author: "Anna", ForwardedFrom: "@source", Text: "quote"},
+			want: "Anna:\n↪ @source:\nquote",
+		},
+		{
+			name: "poll",
+			post: Post{Poll: &Poll{Question: "Why?", Options: []string{"yes", "no"}}},
+			want: "📊 Why? (yes / no)",
+		},
+		{
+			name: "comments",
+			post: Post{Text: "post", Comments: []*Post{{Text: "reply"}}},
+			want: "post\n💬\n— reply",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.post.Content(); got != tt.want {
+				t.Errorf("Content() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostExcerpt(t *testing.T) {
+
+	post := Post{Text: "one  two\nthree"}
+
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{20, "one two three"},
+		{13, "one two three"},
+		{8, "one two…"},
+		{4, "one…"},
+	}
+
+	for _, tt := range tests {
+		if got := post.Excerpt(tt.limit); got != tt.want {
+			t.Errorf("Excerpt(%d) = %q, want %q", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const MinScheduleInterval = 30 * time.Minute
+
+var (
+	ErrInvalidSchedule     = errors.New("invalid schedule")
+	ErrScheduleTooFrequent = errors.New("schedule too frequent")
+)
+
+var daysPattern = regexp.MustCompile(`^(mon|tue|wed|thu|fri|sat|sun)(-(mon|tue|wed|thu|fri|sat|sun))?(,(mon|tue|wed|thu|fri|sat|sun)(-(mon|tue|wed|thu|fri|sat|sun))?)*$`)
+
+// Schedule describes when digests are delivered. Exactly one of the forms is set:
+// daily (or weekday-restricted) times, a fixed interval or a raw cron expression.
+type Schedule struct {
+	Days     string
+	Times    []string
+	Interval time.Duration
+	Cron     string
+}
+
+// ParseSchedule accepts "HH:MM", "HH:MM,HH:MM", "mon-fri HH:MM",
+// "every 3h" and raw five-field cron expressions.
+func ParseSchedule(raw string) (Schedule, error) {
+
+	fields := strings.Fields(strings.ToLower(raw))
+
+	switch {
+
+	case len(fields) == 2 && fields[0] == "every":
+		return parseInterval(fields[1])
+
+	case len(fields) == 5:
+		return parseCron(strings.Join(fields, " "))
+
+	case len(fields) == 2:
+		if !daysPattern.MatchString(fields[0]) {
+			return Schedule{}, fmt.Errorf("%w: unknown days %q", ErrInvalidSchedule, fields[0])
+		}
+
+		times, err := parseTimes(fields[1])
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		return Schedule{Days: fields[0], Times: times}, nil
+
+	case len(fields) == 1:
+		times, err := parseTimes(fields[0])
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		return Schedule{Times: times}, nil
+	}
+
+	return Schedule{}, fmt.Errorf("%w: %q", ErrInvalidSchedule, raw)
+}
+
+func (s Schedule) IsZero() bool {
+	return len(s.Times) == 0 && s.Interval == 0 && s.Cron == ""
+}
+
+func (s Schedule) String() string {
+
+	switch {
+
+	case s.Interval != 0:
+		if s.Interval%time.Hour == 0 {
+			return fmt.Sprintf("every %dh", s.Interval/time.Hour)
+		}
+		return fmt.Sprintf("every %dm", s.Interval/time.Minute)
+
+	case s.Cron != "":
+		return s.Cron
+
+	case s.Days != "":
+		return s.Days + " " + strings.Join(s.Times, ",")
+	}
+
+	return strings.Join(s.Times, ",")
+}
+
+// CronSpecs returns standard cron specs which together make up the schedule.
+func (s Schedule) CronSpecs() []string {
+
+	switch {
+
+	case s.Interval != 0:
+		return []string{"@every " + s.Interval.String()}
+
+	case s.Cron != "":
+		return []string{s.Cron}
+	}
+
+	days := s.Days
+	if days == "" {
+		days = "*"
+	}
+
+	specs := make([]string, 0, len(s.Times))
+	for _, t := range s.Times {
+		var hour, minute int
+		fmt.Sscanf(t, "%d:%d", &hour, &minute)
+		specs = append(specs, fmt.Sprintf("%d %d * * %s", minute, hour, days))
+	}
+
+	return specs
+}
+
+// parseTimes reads comma separated "HH:MM" times, the gaps between them,
+// including the one from the last time to the first on the next day, must
+// be at least MinScheduleInterval.
+func parseTimes(raw string) ([]string, error) {
+
+	var parsed []time.Time
+	for _, part := range strings.Split(raw, ",") {
+
+		t, err := time.Parse("15:04", part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid time %q", ErrInvalidSchedule, part)
+		}
+
+		parsed = append(parsed, t)
+	}
+
+	slices.SortFunc(parsed, time.Time.Compare)
+	parsed = slices.CompactFunc(parsed, time.Time.Equal)
+
+	times := make([]string, 0, len(parsed))
+	for i, t := range parsed {
+
+		next := parsed[0].Add(24 * time.Hour)
+		if i+1 < len(parsed) {
+			next = parsed[i+1]
+		}
+		if next.Sub(t) < MinScheduleInterval {
+			return nil, ErrScheduleTooFrequent
+		}
+
+		times = append(times, t.Format("15:04"))
+	}
+
+	return times, nil
+}
+
+func parseInterval(raw string) (Schedule, error) {
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval%time.Minute != 0 {
+		return Schedule{}, fmt.Errorf("%w: invalid interval %q", ErrInvalidSchedule, raw)
+	}
+
+	if interval < MinScheduleInterval {
+		return Schedule{}, ErrScheduleTooFrequent
+	}
+
+	return Schedule{Interval: interval}, nil
+}
+
+func parseCron(raw string) (Schedule, error) {
+
+	// descriptors and timezones are managed by the service itself
+	if strings.ContainsAny(raw, "@=") {
+		return Schedule{}, fmt.Errorf("%w: unsupported cron expression %q", ErrInvalidSchedule, raw)
+	}
+
+	spec, err := cron.ParseStandard(raw)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %s", ErrInvalidSchedule, err)
+	}
+
+	// sampling upcoming runs is enough to reject "every minute" style expressions
+	next := spec.Next(time.Now())
+	for range 24 {
+		following := spec.Next(next)
+		if following.Sub(next) < MinScheduleInterval {
+			return Schedule{}, ErrScheduleTooFrequent
+		}
+		next = following
+	}
+
+	return Schedule{Cron: raw}, nil
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+
+	tests := []struct {
+		raw     string
+		want    Schedule
+		specs   []string
+		wantErr error
+	}{
+		{raw: "09:00", want: Schedule{Times: []string{"09:00"}}, specs: []string{"0 9 * * *"}},
+		{raw: "9:05", want: Schedule{Times: []string{"09:05"}}, specs: []string{"5 9 * * *"}},
+		{raw: "18:30,09:00", want: Schedule{Times: []string{"09:00", "18:30"}}, specs: []string{"0 9 * * *", "30 18 * * *"}},
+		{raw: "09:00,09:00", want: Schedule{Times: []string{"09:00"}}, specs: []string{"0 9 * * *"}},
+		{raw: "00:00,23:30", want: Schedule{Times: []string{"00:00", "23:30"}}, specs: []string{"0 0 * * *", "30 23 * * *"}},
+		{raw: "MON-FRI 08:30", want: Schedule{Days: "mon-fri", Times: []string{"08:30"}}, specs: []string{"30 8 * * mon-fri"}},
+		{raw: "sat,sun 10:00", want: Schedule{Days: "sat,sun", Times: []string{"10:00"}}, specs: []string{"0 10 * * sat,sun"}},
+		{raw: "every 3h", want: Schedule{Interval: 3 * time.Hour}, specs: []string{"@every 3h0m0s"}},
+		{raw: "every 90m", want: Schedule{Interval: 90 * time.Minute}, specs: []string{"@every 1h30m0s"}},
+		{raw: "0 9 * * 1-5", want: Schedule{Cron: "0 9 * * 1-5"}, specs: []string{"0 9 * * 1-5"}},
+
+		{raw: "", wantErr: ErrInvalidSchedule},
+		{raw: "9", wantErr: ErrInvalidSchedule},
+		{raw: "24:00", wantErr: ErrInvalidSchedule},
+		{raw: "09:60", wantErr: ErrInvalidSchedule},
+		{raw: "09:5", wantErr: ErrInvalidSchedule},
+		{raw: "09:00junk", wantErr: ErrInvalidSchedule},
+		{raw: "09:00,", wantErr: ErrInvalidSchedule},
+		{raw: "-1:00", wantErr: ErrInvalidSchedule},
+		{raw: "someday 09:00", wantErr: ErrInvalidSchedule},
+		{raw: "every soon", wantErr: ErrInvalidSchedule},
+		{raw: "every 90s", wantErr: ErrInvalidSchedule},
+		{raw: "@daily * * * *", wantErr: ErrInvalidSchedule},
+		{raw: "61 * * * *", wantErr: ErrInvalidSchedule},
+
+		{raw: "09:00,09:15", wantErr: ErrScheduleTooFrequent},
+		{raw: "09:15,09:00,18:00", wantErr: ErrScheduleTooFrequent},
+		{raw: "23:50,00:10", wantErr: ErrScheduleTooFrequent},
+		{raw: "every 10m", wantErr: ErrScheduleTooFrequent},
+		{raw: "*/5 * * * *", wantErr: ErrScheduleTooFrequent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+
+			got, err := ParseSchedule(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSchedule(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Days != tt.want.Days || !slices.Equal(got.Times, tt.want.Times) || got.Interval != tt.want.Interval || got.Cron != tt.want.Cron {
+				t.Errorf("ParseSchedule(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+			if specs := got.CronSpecs(); !slices.Equal(specs, tt.specs) {
+				t.Errorf("CronSpecs() = %v, want %v", specs, tt.specs)
+			}
+
+			// the string form is what gets stored and parsed back
+			again, err := ParseSchedule(got.String())
+			if err != nil || again.String() != got.String() {
+				t.Errorf("ParseSchedule(%q) = %v, %v, want it to round trip", got.String(), again, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// SentPost remembers a post included in a digest, so the chat can be told
+// when the post is edited or deleted later.
+type SentPost struct {
+	ChatID    int64
+	ChannelID int64
+	PostID    int64
+	Link      string
+	Excerpt   string
+	SentAt    time.Time
+}
@@ -0,0 +1,17 @@
+package entity
+
+type ChatSettings struct {
+	ChatID   int64
+	Timezone string
+	Language Language
+	Analysis AnalysisOptions
+}
+
+// OutputLanguage is the language set for the chat or the default one.
+func (s ChatSettings) OutputLanguage() Language {
+
+	if s.Language == "" {
+		return DefaultLanguage
+	}
+	return s.Language
+}
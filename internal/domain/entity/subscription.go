@@ -1,10 +1,19 @@
 package entity
 
 type Subscription struct {
+	ID                int64
 	ChatID            int64
 	ChannelID         int64
 	ChannelUsername   string
+	ChannelTitle      string
+	InviteHash        string
+	FeedURL           string
 	LastCheckedPostID int64
-	SendingTime       string
+	Schedule          Schedule
+	Timezone          string
 	ScheduleID        int
+	Active            bool
+
+	ReadOptions
+	Analysis AnalysisOptions
 }
@@ -0,0 +1,205 @@
+package presenter
+
+import (
+	"fmt"
+	"strings"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+// MessageID names a text of the message catalog.
+type MessageID string
+
+// replies to commands
+const (
+	MsgGreeting MessageID = "greeting"
+
+	MsgMonitorFailed MessageID = "monitor.failed"
+	MsgMonitorDone   MessageID = "monitor.done"
+
+	MsgUnsubscribeFailed MessageID = "unsubscribe.failed"
+	MsgUnsubscribeDone   MessageID = "unsubscribe.done"
+
+	MsgEditFailed MessageID = "edit.failed"
+	MsgEditDone   MessageID = "edit.done"
+
+	MsgDigestPreparing MessageID = "digest.preparing"
+	MsgDigestFailed    MessageID = "digest.failed"
+
+	MsgListFailed     MessageID = "list.failed"
+	MsgListEmpty      MessageID = "list.empty"
+	MsgListHeader     MessageID = "list.header"
+	MsgListItem       MessageID = "list.item"
+	MsgListActive     MessageID = "list.active"
+	MsgListPaused     MessageID = "list.paused"
+	MsgPageBack       MessageID = "page.back"
+	MsgPageForward    MessageID = "page.forward"
+	MsgTimezoneFailed MessageID = "timezone.failed"
+	MsgTimezoneDone   MessageID = "timezone.done"
+
+	MsgOptionsFailed MessageID = "options.failed"
+	MsgOptionsDone   MessageID = "options.done"
+	MsgModelFailed   MessageID = "model.failed"
+	MsgPromptFailed  MessageID = "prompt.failed"
+	MsgModelDone     MessageID = "model.done"
+	MsgPromptDone    MessageID = "prompt.done"
+
+	MsgLanguageFailed MessageID = "language.failed"
+	MsgLanguageDone   MessageID = "language.done"
+
+	MsgPauseFailed    MessageID = "pause.failed"
+	MsgPauseDone      MessageID = "pause.done"
+	MsgResumeFailed   MessageID = "resume.failed"
+	MsgResumeQuestion MessageID = "resume.question"
+	MsgResumeCatchUp  MessageID = "resume.catchup"
+	MsgResumeFromNow  MessageID = "resume.now"
+	MsgResumeDone     MessageID = "resume.done"
+
+	MsgBundleFailed   MessageID = "bundle.failed"
+	MsgBundleDone     MessageID = "bundle.done"
+	MsgUnbundleFailed MessageID = "unbundle.failed"
+	MsgUnbundleDone   MessageID = "unbundle.done"
+	MsgBundlesFailed  MessageID = "bundles.failed"
+	MsgBundlesEmpty   MessageID = "bundles.empty"
+	MsgBundlesHeader  MessageID = "bundles.header"
+	MsgBundlesItem    MessageID = "bundles.item"
+
+	MsgWizardChannel   MessageID = "wizard.channel"
+	MsgWizardNotFound  MessageID = "wizard.notfound"
+	MsgWizardFound     MessageID = "wizard.found"
+	MsgWizardCancel    MessageID = "wizard.cancel"
+	MsgWizardCancelled MessageID = "wizard.cancelled"
+)
+
+// user client authorization in the admin chat
+const (
+	MsgAuthCode         MessageID = "auth.code"
+	MsgAuthQR           MessageID = "auth.qr"
+	MsgAuthDone         MessageID = "auth.done"
+	MsgAuthCodeAccepted MessageID = "auth.code.accepted"
+	MsgAuthCodeInvalid  MessageID = "auth.code.invalid"
+)
+
+// digest texts
+const (
+	MsgDigestPosts       MessageID = "digest.posts"
+	MsgTruncatedHeader   MessageID = "digest.truncated"
+	MsgLimitedHeader     MessageID = "digest.limited"
+	MsgRankedHeader      MessageID = "digest.ranked"
+	MsgEngagementHeader  MessageID = "digest.engagement"
+	MsgEngagementItem    MessageID = "digest.engagement.item"
+	MsgBundleHeader      MessageID = "digest.bundle"
+	MsgCorrectionsHeader MessageID = "corrections.header"
+	MsgPostDeleted       MessageID = "corrections.deleted"
+	MsgPostEdited        MessageID = "corrections.edited"
+
+	PluralPosts       MessageID = "plural.posts"
+	PluralSubscribers MessageID = "plural.subscribers"
+	PluralViews       MessageID = "plural.views"
+	PluralForwards    MessageID = "plural.forwards"
+	PluralReactions   MessageID = "plural.reactions"
+)
+
+// errors
+const (
+	ErrMsgInternal MessageID = "error.internal"
+
+	ErrMsgArgNumber         MessageID = "error.args"
+	ErrMsgChannelArgNumber  MessageID = "error.args.channel"
+	ErrMsgOptionalArgNumber MessageID = "error.args.optional"
+	ErrMsgEditArgNumber     MessageID = "error.args.edit"
+	ErrMsgBundleArgNumber   MessageID = "error.args.bundle"
+	ErrMsgOptionsArgNumber  MessageID = "error.args.options"
+	ErrMsgModelArgNumber    MessageID = "error.args.model"
+	ErrMsgPromptArgNumber   MessageID = "error.args.prompt"
+	ErrMsgSingleArgNumber   MessageID = "error.args.single"
+
+	ErrMsgBundleName       MessageID = "error.bundle.name"
+	ErrMsgUnknownTimezone  MessageID = "error.timezone"
+	ErrMsgUnknownLanguage  MessageID = "error.language"
+	ErrMsgUnknownOption    MessageID = "error.option"
+	ErrMsgOptionValue      MessageID = "error.option.value"
+	ErrMsgUnknownModelOpt  MessageID = "error.model.option"
+	ErrMsgModelOptionValue MessageID = "error.model.value"
+	ErrMsgModelNotAllowed  MessageID = "error.model.allowed"
+	ErrMsgPromptLength     MessageID = "error.prompt.length"
+	ErrMsgInvalidSchedule  MessageID = "error.schedule"
+	ErrMsgScheduleFrequent MessageID = "error.schedule.frequent"
+	ErrMsgHoursFormat      MessageID = "error.hours.format"
+	ErrMsgHoursValue       MessageID = "error.hours.value"
+	ErrMsgShortUsername    MessageID = "error.username.short"
+	ErrMsgNameCharacters   MessageID = "error.username.characters"
+	ErrMsgChannelNotFound  MessageID = "error.channel.notfound"
+	ErrMsgInviteLink       MessageID = "error.invite"
+	ErrMsgFeedURL          MessageID = "error.feed.url"
+
+	ErrMsgSubscriptionNotFound MessageID = "error.subscription.notfound"
+	ErrMsgBundleExists         MessageID = "error.bundle.exists"
+	ErrMsgBundleNotFound       MessageID = "error.bundle.notfound"
+	ErrMsgSubscriptionExists   MessageID = "error.subscription.exists"
+
+	ErrMsgNoPosts           MessageID = "error.posts.none"
+	ErrMsgInviteRequestSent MessageID = "error.invite.request"
+	ErrMsgInviteNotFound    MessageID = "error.invite.notfound"
+	ErrMsgFloodWait         MessageID = "error.floodwait"
+
+	ErrMsgFeedNoPosts     MessageID = "error.feed.posts"
+	ErrMsgFeedUnavailable MessageID = "error.feed.unavailable"
+	ErrMsgFeedFormat      MessageID = "error.feed.format"
+)
+
+var catalogs = map[entity.Language]map[MessageID]string{
+	entity.LanguageRussian: russian,
+	entity.LanguageEnglish: english,
+	entity.LanguageGerman:  german,
+}
+
+// Message is a catalog text with the arguments of its format verbs.
+type Message struct {
+	ID   MessageID
+	Args []any
+}
+
+func message(id MessageID, args ...any) Message {
+	return Message{ID: id, Args: args}
+}
+
+// Text renders the message in the language, falling back to the default
+// language for texts missing from its catalog.
+func Text(language entity.Language, id MessageID, args ...any) string {
+
+	text, ok := catalogs[language][id]
+	if !ok {
+		text = catalogs[entity.DefaultLanguage][id]
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Plural renders n with the word form the language requires. Plural texts
+// list their forms separated by "|": one, few and many for Russian, one
+// and other for the rest.
+func Plural(language entity.Language, n int, id MessageID) string {
+
+	forms := strings.Split(Text(language, id), "|")
+
+	form := forms[len(forms)-1]
+	switch {
+
+	case len(forms) == 3:
+		switch n10, n100 := n%10, n%100; {
+		case n10 == 1 && n100 != 11:
+			form = forms[0]
+		case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+			form = forms[1]
+		}
+
+	case n == 1:
+		form = forms[0]
+	}
+
+	return fmt.Sprintf("%d %s", n, form)
+}
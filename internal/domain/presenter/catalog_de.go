@@ -0,0 +1,129 @@
+package presenter
+
+var german = map[MessageID]string{
+	MsgGreeting: "Hallo! Der Bot ist einsatzbereit!",
+
+	MsgMonitorFailed: "Der Kanal wurde nicht zur Überwachung hinzugefügt!\n",
+	MsgMonitorDone:   "Erledigt! Der Kanal wurde zur Überwachung hinzugefügt!",
+
+	MsgUnsubscribeFailed: "Das Abonnement des Kanals konnte nicht beendet werden!\n",
+	MsgUnsubscribeDone:   "Erledigt! Der Kanal wurde aus der Überwachung entfernt!",
+
+	MsgEditFailed: "Der Zeitplan wurde nicht geändert!\n",
+	MsgEditDone:   "Erledigt! Der Versandzeitplan wurde geändert!",
+
+	MsgDigestPreparing: "Die Zusammenfassung wird erstellt, das kann ein paar Minuten dauern...",
+	MsgDigestFailed:    "Die Zusammenfassung konnte nicht erstellt werden!\n",
+
+	MsgListFailed:     "Die Liste der Abonnements konnte nicht abgerufen werden!\n",
+	MsgListEmpty:      "Sie haben noch keine aktiven Abonnements.",
+	MsgListHeader:     "Aktive Abonnements (Seite %d von %d):\nZeitzone: %s\n",
+	MsgListItem:       "\n%s — %s\nZeitplan: %s\nLetzter Beitrag: %d\nNächster Lauf: %s\n",
+	MsgListActive:     "aktiv",
+	MsgListPaused:     "pausiert",
+	MsgPageBack:       "« Zurück",
+	MsgPageForward:    "Weiter »",
+	MsgTimezoneFailed: "Die Zeitzone wurde nicht geändert!\n",
+	MsgTimezoneDone:   "Erledigt! Die Zeitzone ist eingestellt, die Versandzeiten wurden neu berechnet!",
+
+	MsgOptionsFailed: "Die Leseoptionen wurden nicht geändert!\n",
+	MsgOptionsDone:   "Erledigt! Leseoptionen des Kanals: %s",
+	MsgModelFailed:   "Die Modelleinstellungen wurden nicht geändert!\n",
+	MsgPromptFailed:  "Der System-Prompt wurde nicht geändert!\n",
+	MsgModelDone:     "Erledigt! Modelleinstellungen: %s",
+	MsgPromptDone:    "Erledigt! Der System-Prompt wurde geändert, Modelleinstellungen: %s",
+
+	MsgLanguageFailed: "Die Sprache wurde nicht geändert!\n",
+	MsgLanguageDone:   "Erledigt! Der Bot antwortet auf Deutsch, auch die Zusammenfassungen werden auf Deutsch verfasst.",
+
+	MsgPauseFailed:    "Die Abonnements wurden nicht pausiert!\n",
+	MsgPauseDone:      "Erledigt! Die Abonnements sind pausiert, mit /resume werden sie fortgesetzt.",
+	MsgResumeFailed:   "Die Abonnements wurden nicht fortgesetzt!\n",
+	MsgResumeQuestion: "Ab wann soll der Versand fortgesetzt werden?",
+	MsgResumeCatchUp:  "Verpasstes nachholen",
+	MsgResumeFromNow:  "Ab jetzt beginnen",
+	MsgResumeDone:     "Erledigt! Die Abonnements wurden fortgesetzt!",
+
+	MsgBundleFailed:   "Die Sammlung wurde nicht erstellt!\n",
+	MsgBundleDone:     "Erledigt! Die Sammlung wurde erstellt, Neuigkeiten aus ihren Kanälen kommen in einer Nachricht!",
+	MsgUnbundleFailed: "Die Sammlung wurde nicht gelöscht!\n",
+	MsgUnbundleDone:   "Erledigt! Die Sammlung wurde gelöscht!",
+	MsgBundlesFailed:  "Die Liste der Sammlungen konnte nicht abgerufen werden!\n",
+	MsgBundlesEmpty:   "Sie haben noch keine Sammlungen.",
+	MsgBundlesHeader:  "Sammlungen:\n",
+	MsgBundlesItem:    "\n%s: %s\nZeitplan: %s\nNächster Lauf: %s\n",
+
+	MsgWizardChannel:   "Senden Sie den Benutzernamen, den Link oder den Einladungslink des Kanals oder die Adresse eines RSS- oder Atom-Feeds, der überwacht werden soll.",
+	MsgWizardNotFound:  "Kanal nicht gefunden, senden Sie einen anderen Benutzernamen!\n",
+	MsgWizardFound:     "Kanal %s gefunden. Wählen Sie die Versandzeit:",
+	MsgWizardCancel:    "Abbrechen",
+	MsgWizardCancelled: "Das Hinzufügen des Kanals wurde abgebrochen.",
+
+	MsgAuthCode:         "Das Telegram-Benutzerkonto muss sich anmelden.\nSenden Sie den Code aus dem Telegram-Chat mit durch Leerzeichen getrennten Ziffern, zum Beispiel: 1 2 3 4 5.",
+	MsgAuthQR:           "Das Telegram-Benutzerkonto muss sich anmelden.\nScannen Sie den QR-Code in der App: Einstellungen → Geräte → Desktop-Gerät verbinden.\nDer Code ist bis %s gültig.",
+	MsgAuthDone:         "Das Benutzerkonto ist angemeldet.",
+	MsgAuthCodeAccepted: "Code angenommen, Anmeldung läuft...",
+	MsgAuthCodeInvalid:  "Der Code muss aus Ziffern bestehen, senden Sie ihn erneut.",
+
+	MsgDigestPosts:       "%s in der Zusammenfassung",
+	MsgTruncatedHeader:   "Die Zusammenfassung umfasst die letzten %d Beiträge, etwa %d weitere wurden wegen des Limits nicht verarbeitet.\n\n",
+	MsgLimitedHeader:     "Die Zusammenfassung umfasst die letzten %d Beiträge, ältere wurden wegen des Limits nicht verarbeitet.\n\n",
+	MsgRankedHeader:      "Von %d Beiträgen wurden die %d auffälligsten analysiert.\n\n",
+	MsgEngagementHeader:  "\n\nMeiste Resonanz:\n",
+	MsgEngagementItem:    "• %s „%s“ — %s, %s, %s\n%s\n",
+	MsgBundleHeader:      "Sammlung „%s“\n\n",
+	MsgCorrectionsHeader: "Korrekturen zu früheren Zusammenfassungen:\n\n",
+	MsgPostDeleted:       "• %s: Beitrag „%s“ wurde gelöscht",
+	MsgPostEdited:        "• %s: Beitrag „%s“ wurde bearbeitet, %s\nJetzt: „%s“",
+
+	PluralPosts:       "Beitrag|Beiträge",
+	PluralSubscribers: "Abonnent|Abonnenten",
+	PluralViews:       "Aufruf|Aufrufe",
+	PluralForwards:    "Weiterleitung|Weiterleitungen",
+	PluralReactions:   "Reaktion|Reaktionen",
+
+	ErrMsgInternal: "Interner Dienstfehler. Bitte versuchen Sie es später erneut.",
+
+	ErrMsgArgNumber:         "Senden Sie 2 Angaben: den Kanal und den Versandzeitplan.",
+	ErrMsgChannelArgNumber:  "Senden Sie den Kanal und bei Bedarf den Versandzeitplan.",
+	ErrMsgOptionalArgNumber: "Senden Sie den Kanal und höchstens eine weitere Angabe.",
+	ErrMsgEditArgNumber:     "Senden Sie den Kanal, den aktuellen und den neuen Zeitplan, getrennt durch einen Pfeil, zum Beispiel: @channel 09:00 -> mon-fri 08:30.",
+	ErrMsgBundleArgNumber:   "Senden Sie den Namen der Sammlung, mindestens 2 Kanäle und den Zeitplan, zum Beispiel: morning @a @b 08:00.",
+	ErrMsgOptionsArgNumber:  "Senden Sie den Kanal und mindestens eine Option, zum Beispiel: @group topic=5 bots=off comments=on.",
+	ErrMsgModelArgNumber:    "Senden Sie mindestens eine Modelleinstellung und bei Bedarf den Kanal davor, zum Beispiel: @channel model=openai/gpt-4o-mini temperature=0.3.",
+	ErrMsgPromptArgNumber:   "Senden Sie den Text des System-Prompts oder default und bei Bedarf den Kanal davor.",
+	ErrMsgSingleArgNumber:   "Senden Sie genau 1 Angabe.",
+
+	ErrMsgBundleName:       "Der Name einer Sammlung darf nur Buchstaben, Ziffern, _ und - enthalten (höchstens 32 Zeichen).",
+	ErrMsgUnknownTimezone:  "Unbekannte Zeitzone. Verwenden Sie das IANA-Format, zum Beispiel: Europe/Berlin.",
+	ErrMsgUnknownLanguage:  "Unbekannte Sprache. Verfügbar: %s.",
+	ErrMsgUnknownOption:    "Unbekannte Option. Verfügbar: topic, bots und comments.",
+	ErrMsgOptionValue:      "Ungültiger Wert. Verwenden Sie topic=ID oder topic=all, bots=on|off, comments=on|off.",
+	ErrMsgUnknownModelOpt:  "Unbekannte Modelleinstellung. Verfügbar: model, temperature und top_p.",
+	ErrMsgModelOptionValue: "Ungültiger Wert. Verwenden Sie model=ID, temperature von 0 bis %d, top_p über 0 und höchstens 1 oder default.",
+	ErrMsgModelNotAllowed:  "Dieses Modell ist nicht verfügbar. Wählen Sie ein anderes Modell oder kehren Sie zu model=default zurück.",
+	ErrMsgPromptLength:     "Der System-Prompt muss 1 bis %d Zeichen lang sein.",
+	ErrMsgInvalidSchedule:  "Zeitpläne werden in diesen Formaten akzeptiert: HH:MM, HH:MM,HH:MM, mon-fri HH:MM, every 3h oder ein Cron-Ausdruck mit 5 Feldern.",
+	ErrMsgScheduleFrequent: "Nachrichten können höchstens einmal alle %d Minuten zugestellt werden.",
+	ErrMsgHoursFormat:      "Der Zeitraum der Zusammenfassung wird in Stunden angegeben, zum Beispiel: 12.",
+	ErrMsgHoursValue:       "Der Zeitraum der Zusammenfassung muss zwischen 1 und %d Stunden liegen.",
+	ErrMsgShortUsername:    "Der Benutzername des Kanals ist zu kurz. Prüfen Sie ihn und senden Sie die Anfrage erneut.",
+	ErrMsgNameCharacters:   "Der Benutzername enthält ungültige Zeichen. Prüfen Sie ihn und senden Sie die Anfrage erneut.",
+	ErrMsgChannelNotFound:  "Kein Kanal mit diesem Benutzernamen gefunden. Prüfen Sie ihn und versuchen Sie es erneut.",
+	ErrMsgInviteLink:       "Der Einladungslink ist ungültig oder abgelaufen oder führt nicht zu einem Kanal.",
+	ErrMsgFeedURL:          "Ungültige Feed-Adresse. Senden Sie den vollständigen RSS- oder Atom-Link, zum Beispiel: https://example.com/feed.xml.",
+
+	ErrMsgSubscriptionNotFound: "Kein Abonnement für diesen Kanal gefunden.",
+	ErrMsgBundleExists:         "Eine Sammlung mit diesem Namen existiert bereits.",
+	ErrMsgBundleNotFound:       "Keine Sammlung mit diesem Namen gefunden.",
+	ErrMsgSubscriptionExists:   "Sie erhalten Neuigkeiten aus diesem Kanal bereits zu dieser Zeit.",
+
+	ErrMsgNoPosts:           "Im Kanal wurden für diesen Zeitraum keine Beiträge gefunden.",
+	ErrMsgInviteRequestSent: "Der Kanal nimmt Mitglieder auf Anfrage auf. Die Anfrage wurde gesendet, versuchen Sie es nach der Freigabe erneut.",
+	ErrMsgInviteNotFound:    "Über diesen Link wurde kein Kanal gefunden. Prüfen Sie ihn und versuchen Sie es erneut.",
+	ErrMsgFloodWait:         "Telegram hat Anfragen vorübergehend begrenzt. Versuchen Sie es in %d Min. erneut.",
+
+	ErrMsgFeedNoPosts:     "Im Feed wurden für diesen Zeitraum keine Einträge gefunden.",
+	ErrMsgFeedUnavailable: "Der Feed unter dieser Adresse konnte nicht geladen werden. Prüfen Sie den Link und versuchen Sie es erneut.",
+	ErrMsgFeedFormat:      "Unter dieser Adresse gibt es keinen RSS- oder Atom-Feed.",
+}
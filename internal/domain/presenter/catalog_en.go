@@ -0,0 +1,129 @@
+package presenter
+
+var english = map[MessageID]string{
+	MsgGreeting: "Hi! The bot is ready to work!",
+
+	MsgMonitorFailed: "The channel was not added to monitoring!\n",
+	MsgMonitorDone:   "Done! The channel has been added to monitoring!",
+
+	MsgUnsubscribeFailed: "Could not unsubscribe from the channel!\n",
+	MsgUnsubscribeDone:   "Done! The channel has been removed from monitoring!",
+
+	MsgEditFailed: "The schedule was not changed!\n",
+	MsgEditDone:   "Done! The delivery schedule has been changed!",
+
+	MsgDigestPreparing: "Preparing the digest, this may take a couple of minutes...",
+	MsgDigestFailed:    "Could not prepare the digest!\n",
+
+	MsgListFailed:     "Could not get the list of subscriptions!\n",
+	MsgListEmpty:      "You have no active subscriptions yet.",
+	MsgListHeader:     "Active subscriptions (page %d of %d):\nTime zone: %s\n",
+	MsgListItem:       "\n%s — %s\nSchedule: %s\nLast post: %d\nNext run: %s\n",
+	MsgListActive:     "active",
+	MsgListPaused:     "paused",
+	MsgPageBack:       "« Back",
+	MsgPageForward:    "Next »",
+	MsgTimezoneFailed: "The time zone was not changed!\n",
+	MsgTimezoneDone:   "Done! The time zone is set and delivery times have been recalculated!",
+
+	MsgOptionsFailed: "The reading options were not changed!\n",
+	MsgOptionsDone:   "Done! Channel reading options: %s",
+	MsgModelFailed:   "The model settings were not changed!\n",
+	MsgPromptFailed:  "The system prompt was not changed!\n",
+	MsgModelDone:     "Done! Model settings: %s",
+	MsgPromptDone:    "Done! The system prompt has been changed, model settings: %s",
+
+	MsgLanguageFailed: "The language was not changed!\n",
+	MsgLanguageDone:   "Done! The bot will reply in English and digests will be written in English too.",
+
+	MsgPauseFailed:    "The subscriptions were not paused!\n",
+	MsgPauseDone:      "Done! The subscriptions are paused, use /resume to resume them.",
+	MsgResumeFailed:   "The subscriptions were not resumed!\n",
+	MsgResumeQuestion: "Where should delivery continue from?",
+	MsgResumeCatchUp:  "Catch up on missed posts",
+	MsgResumeFromNow:  "Start from now",
+	MsgResumeDone:     "Done! The subscriptions have been resumed!",
+
+	MsgBundleFailed:   "The bundle was not created!\n",
+	MsgBundleDone:     "Done! The bundle has been created, news from its channels will arrive in one message!",
+	MsgUnbundleFailed: "The bundle was not deleted!\n",
+	MsgUnbundleDone:   "Done! The bundle has been deleted!",
+	MsgBundlesFailed:  "Could not get the list of bundles!\n",
+	MsgBundlesEmpty:   "You have no bundles yet.",
+	MsgBundlesHeader:  "Bundles:\n",
+	MsgBundlesItem:    "\n%s: %s\nSchedule: %s\nNext run: %s\n",
+
+	MsgWizardChannel:   "Send the username, link or invite link of the channel, or the address of an RSS or Atom feed to monitor.",
+	MsgWizardNotFound:  "Channel not found, send another username!\n",
+	MsgWizardFound:     "Channel %s found. Choose the delivery time:",
+	MsgWizardCancel:    "Cancel",
+	MsgWizardCancelled: "Adding the channel was cancelled.",
+
+	MsgAuthCode:         "The Telegram user account needs to log in.\nSend the code from the Telegram chat with its digits separated by spaces, for example: 1 2 3 4 5.",
+	MsgAuthQR:           "The Telegram user account needs to log in.\nScan the QR code in the app: Settings → Devices → Link Desktop Device.\nThe code is valid until %s.",
+	MsgAuthDone:         "The user account is logged in.",
+	MsgAuthCodeAccepted: "Code accepted, logging in...",
+	MsgAuthCodeInvalid:  "The code must consist of digits, send it again.",
+
+	MsgDigestPosts:       "%s in the digest",
+	MsgTruncatedHeader:   "The digest covers the latest %d posts, about %d more were not processed because of the limit.\n\n",
+	MsgLimitedHeader:     "The digest covers the latest %d posts, earlier ones were not processed because of the limit.\n\n",
+	MsgRankedHeader:      "Of %d posts, the %d most notable were analyzed.\n\n",
+	MsgEngagementHeader:  "\n\nMost engagement:\n",
+	MsgEngagementItem:    "• %s “%s” — %s, %s, %s\n%s\n",
+	MsgBundleHeader:      "Bundle “%s”\n\n",
+	MsgCorrectionsHeader: "Corrections to earlier digests:\n\n",
+	MsgPostDeleted:       "• %s: post “%s” was deleted",
+	MsgPostEdited:        "• %s: post “%s” was edited, %s\nNow: “%s”",
+
+	PluralPosts:       "post|posts",
+	PluralSubscribers: "subscriber|subscribers",
+	PluralViews:       "view|views",
+	PluralForwards:    "repost|reposts",
+	PluralReactions:   "reaction|reactions",
+
+	ErrMsgInternal: "Internal service error. Please try again later.",
+
+	ErrMsgArgNumber:         "Send 2 options: the channel and the delivery schedule.",
+	ErrMsgChannelArgNumber:  "Send the channel and, if needed, the delivery schedule.",
+	ErrMsgOptionalArgNumber: "Send the channel and at most one more option.",
+	ErrMsgEditArgNumber:     "Send the channel, the current and the new schedule separated by an arrow, for example: @channel 09:00 -> mon-fri 08:30.",
+	ErrMsgBundleArgNumber:   "Send the bundle name, at least 2 channels and the schedule, for example: morning @a @b 08:00.",
+	ErrMsgOptionsArgNumber:  "Send the channel and at least one option, for example: @group topic=5 bots=off comments=on.",
+	ErrMsgModelArgNumber:    "Send at least one model setting and, if needed, the channel before them, for example: @channel model=openai/gpt-4o-mini temperature=0.3.",
+	ErrMsgPromptArgNumber:   "Send the system prompt text or default and, if needed, the channel before it.",
+	ErrMsgSingleArgNumber:   "Send exactly 1 option.",
+
+	ErrMsgBundleName:       "A bundle name may only contain letters, digits, _ and - (up to 32 characters).",
+	ErrMsgUnknownTimezone:  "Unknown time zone. Use the IANA format, for example: Europe/Berlin.",
+	ErrMsgUnknownLanguage:  "Unknown language. Available: %s.",
+	ErrMsgUnknownOption:    "Unknown option. Available: topic, bots and comments.",
+	ErrMsgOptionValue:      "Invalid option value. Use topic=ID or topic=all, bots=on|off, comments=on|off.",
+	ErrMsgUnknownModelOpt:  "Unknown model setting. Available: model, temperature and top_p.",
+	ErrMsgModelOptionValue: "Invalid setting value. Use model=ID, temperature from 0 to %d, top_p above 0 and up to 1, or default.",
+	ErrMsgModelNotAllowed:  "This model is not available. Choose another model or go back to model=default.",
+	ErrMsgPromptLength:     "The system prompt must be 1 to %d characters long.",
+	ErrMsgInvalidSchedule:  "Schedules are accepted as: HH:MM, HH:MM,HH:MM, mon-fri HH:MM, every 3h or a 5-field cron expression.",
+	ErrMsgScheduleFrequent: "Messages can be delivered at most once every %d minutes.",
+	ErrMsgHoursFormat:      "The digest period is given in hours, for example: 12.",
+	ErrMsgHoursValue:       "The digest period must be from 1 to %d hours.",
+	ErrMsgShortUsername:    "The channel username is too short. Check it and send the request again.",
+	ErrMsgNameCharacters:   "The username contains invalid characters. Check it and send the request again.",
+	ErrMsgChannelNotFound:  "Could not find a channel with this username. Check it and try again.",
+	ErrMsgInviteLink:       "The invite link is invalid or expired, or does not lead to a channel.",
+	ErrMsgFeedURL:          "Invalid feed address. Send the full RSS or Atom link, for example: https://example.com/feed.xml.",
+
+	ErrMsgSubscriptionNotFound: "No subscription to this channel was found.",
+	ErrMsgBundleExists:         "A bundle with this name already exists.",
+	ErrMsgBundleNotFound:       "No bundle with this name was found.",
+	ErrMsgSubscriptionExists:   "You already receive news from this channel at this time.",
+
+	ErrMsgNoPosts:           "No posts were found in the channel for this period.",
+	ErrMsgInviteRequestSent: "The channel accepts members by request. The request has been sent, try again once it is approved.",
+	ErrMsgInviteNotFound:    "Could not find a channel by this link. Check it and try again.",
+	ErrMsgFloodWait:         "Telegram has temporarily limited requests. Try again in %d min.",
+
+	ErrMsgFeedNoPosts:     "No entries were found in the feed for this period.",
+	ErrMsgFeedUnavailable: "Could not load the feed at this address. Check the link and try again.",
+	ErrMsgFeedFormat:      "There is no RSS or Atom feed at this address.",
+}
@@ -0,0 +1,129 @@
+package presenter
+
+var russian = map[MessageID]string{
+	MsgGreeting: "Привет! Бот готов к работе!",
+
+	MsgMonitorFailed: "Канал не был добавлен в систему мониторинга!\n",
+	MsgMonitorDone:   "Успех! Канал успешно добавлен в систему мониторинга!",
+
+	MsgUnsubscribeFailed: "Не удалось отписаться от канала!\n",
+	MsgUnsubscribeDone:   "Успех! Канал удалён из системы мониторинга!",
+
+	MsgEditFailed: "Расписание не было изменено!\n",
+	MsgEditDone:   "Успех! Расписание отправки сообщений изменено!",
+
+	MsgDigestPreparing: "Готовлю сводку, это может занять пару минут...",
+	MsgDigestFailed:    "Не удалось подготовить сводку!\n",
+
+	MsgListFailed:     "Не удалось получить список подписок!\n",
+	MsgListEmpty:      "У вас пока нет активных подписок.",
+	MsgListHeader:     "Активные подписки (страница %d из %d):\nЧасовой пояс: %s\n",
+	MsgListItem:       "\n%s — %s\nРасписание: %s\nПоследний пост: %d\nСледующий запуск: %s\n",
+	MsgListActive:     "активна",
+	MsgListPaused:     "на паузе",
+	MsgPageBack:       "« Назад",
+	MsgPageForward:    "Вперёд »",
+	MsgTimezoneFailed: "Часовой пояс не был изменён!\n",
+	MsgTimezoneDone:   "Успех! Часовой пояс установлен, время отправки сообщений пересчитано!",
+
+	MsgOptionsFailed: "Настройки чтения не были изменены!\n",
+	MsgOptionsDone:   "Успех! Настройки чтения канала: %s",
+	MsgModelFailed:   "Настройки модели не были изменены!\n",
+	MsgPromptFailed:  "Системный промпт не был изменён!\n",
+	MsgModelDone:     "Успех! Настройки модели: %s",
+	MsgPromptDone:    "Успех! Системный промпт изменён, настройки модели: %s",
+
+	MsgLanguageFailed: "Язык не был изменён!\n",
+	MsgLanguageDone:   "Успех! Бот будет отвечать на русском, сводки тоже будут на русском.",
+
+	MsgPauseFailed:    "Подписки не были поставлены на паузу!\n",
+	MsgPauseDone:      "Успех! Подписки поставлены на паузу, для возобновления используйте /resume.",
+	MsgResumeFailed:   "Подписки не были возобновлены!\n",
+	MsgResumeQuestion: "С какого места продолжить отправку сообщений?",
+	MsgResumeCatchUp:  "Наверстать пропущенное",
+	MsgResumeFromNow:  "Начать с текущего момента",
+	MsgResumeDone:     "Успех! Подписки возобновлены!",
+
+	MsgBundleFailed:   "Подборка не была создана!\n",
+	MsgBundleDone:     "Успех! Подборка создана, новости из её каналов будут приходить одним сообщением!",
+	MsgUnbundleFailed: "Подборка не была удалена!\n",
+	MsgUnbundleDone:   "Успех! Подборка удалена!",
+	MsgBundlesFailed:  "Не удалось получить список подборок!\n",
+	MsgBundlesEmpty:   "У вас пока нет подборок.",
+	MsgBundlesHeader:  "Подборки:\n",
+	MsgBundlesItem:    "\n%s: %s\nРасписание: %s\nСледующий запуск: %s\n",
+
+	MsgWizardChannel:   "Отправьте юзернейм, ссылку или ссылку-приглашение на канал либо адрес RSS- или Atom-ленты, которые нужно отслеживать.",
+	MsgWizardNotFound:  "Канал не найден, отправьте другой юзернейм!\n",
+	MsgWizardFound:     "Канал %s найден. Выберите время отправки сообщений:",
+	MsgWizardCancel:    "Отмена",
+	MsgWizardCancelled: "Добавление канала отменено.",
+
+	MsgAuthCode:         "Требуется вход в пользовательский аккаунт Telegram.\nОтправьте код из чата Telegram, разделив цифры пробелами, например: 1 2 3 4 5.",
+	MsgAuthQR:           "Требуется вход в пользовательский аккаунт Telegram.\nОтсканируйте QR-код в приложении: Настройки → Устройства → Подключить устройство.\nКод действует до %s.",
+	MsgAuthDone:         "Вход в пользовательский аккаунт выполнен.",
+	MsgAuthCodeAccepted: "Код принят, выполняю вход...",
+	MsgAuthCodeInvalid:  "Код должен состоять из цифр, отправьте его ещё раз.",
+
+	MsgDigestPosts:       "%s в сводке",
+	MsgTruncatedHeader:   "В сводку вошли последние %d постов, ещё около %d не были обработаны из-за лимита.\n\n",
+	MsgLimitedHeader:     "В сводку вошли последние %d постов, более ранние не были обработаны из-за лимита.\n\n",
+	MsgRankedHeader:      "Из %d постов в анализ попали %d самых заметных.\n\n",
+	MsgEngagementHeader:  "\n\nБольше всего откликов:\n",
+	MsgEngagementItem:    "• %s «%s» — %s, %s, %s\n%s\n",
+	MsgBundleHeader:      "Подборка «%s»\n\n",
+	MsgCorrectionsHeader: "Поправки к прошлым сводкам:\n\n",
+	MsgPostDeleted:       "• %s: пост «%s» удалён",
+	MsgPostEdited:        "• %s: пост «%s» изменён, %s\nТеперь: «%s»",
+
+	PluralPosts:       "пост|поста|постов",
+	PluralSubscribers: "подписчик|подписчика|подписчиков",
+	PluralViews:       "просмотр|просмотра|просмотров",
+	PluralForwards:    "репост|репоста|репостов",
+	PluralReactions:   "реакция|реакции|реакций",
+
+	ErrMsgInternal: "Внутрення ошибка сервиса. Пожалуйста, повторите запрос позже.",
+
+	ErrMsgArgNumber:         "В бот нужно передать 2 опции: канал и расписание отправки сообщений.",
+	ErrMsgChannelArgNumber:  "В бот нужно передать канал и, при необходимости, расписание отправки сообщений.",
+	ErrMsgOptionalArgNumber: "В бот нужно передать канал и не более одной дополнительной опции.",
+	ErrMsgEditArgNumber:     "В бот нужно передать канал, текущее и новое расписание через стрелку, например: @channel 09:00 -> mon-fri 08:30.",
+	ErrMsgBundleArgNumber:   "В бот нужно передать название подборки, не менее 2 каналов и расписание, например: morning @a @b 08:00.",
+	ErrMsgOptionsArgNumber:  "В бот нужно передать канал и хотя бы одну настройку, например: @group topic=5 bots=off comments=on.",
+	ErrMsgModelArgNumber:    "В бот нужно передать хотя бы одну настройку модели и, при необходимости, канал перед ними, например: @channel model=openai/gpt-4o-mini temperature=0.3.",
+	ErrMsgPromptArgNumber:   "В бот нужно передать текст системного промпта или default и, при необходимости, канал перед ним.",
+	ErrMsgSingleArgNumber:   "В бот нужно передать ровно 1 опцию.",
+
+	ErrMsgBundleName:       "Название подборки может содержать только буквы, цифры, _ и - (не более 32 символов).",
+	ErrMsgUnknownTimezone:  "Неизвестный часовой пояс. Укажите его в формате IANA, например: Europe/Berlin.",
+	ErrMsgUnknownLanguage:  "Неизвестный язык. Доступны: %s.",
+	ErrMsgUnknownOption:    "Неизвестная настройка. Доступны: topic, bots и comments.",
+	ErrMsgOptionValue:      "Некорректное значение настройки. Используйте topic=ID или topic=all, bots=on|off, comments=on|off.",
+	ErrMsgUnknownModelOpt:  "Неизвестная настройка модели. Доступны: model, temperature и top_p.",
+	ErrMsgModelOptionValue: "Некорректное значение настройки. Используйте model=ID, temperature от 0 до %d, top_p больше 0 и не больше 1 или default.",
+	ErrMsgModelNotAllowed:  "Эта модель недоступна. Выберите другую модель или верните model=default.",
+	ErrMsgPromptLength:     "Системный промпт должен содержать от 1 до %d символов.",
+	ErrMsgInvalidSchedule:  "Расписание принимается в форматах: ЧЧ:ММ, ЧЧ:ММ,ЧЧ:ММ, mon-fri ЧЧ:ММ, every 3h или cron-выражение из 5 полей.",
+	ErrMsgScheduleFrequent: "Сообщения можно получать не чаще одного раза в %d минут.",
+	ErrMsgHoursFormat:      "Период сводки принимается в часах, например: 12.",
+	ErrMsgHoursValue:       "Период сводки должен быть от 1 до %d часов.",
+	ErrMsgShortUsername:    "Слишком короткий юзернейм для канала. Проверьте его и отправьте запрос заново.",
+	ErrMsgNameCharacters:   "Юзернейм содержит некорректные символы. Проверьте его и отправьте запрос заново.",
+	ErrMsgChannelNotFound:  "Не удалось найти канал с заданным юзернеймом. Проверьте его и повторите запрос.",
+	ErrMsgInviteLink:       "Ссылка-приглашение недействительна или устарела, либо ведёт не на канал.",
+	ErrMsgFeedURL:          "Некорректный адрес ленты. Отправьте полную ссылку на RSS или Atom, например: https://example.com/feed.xml.",
+
+	ErrMsgSubscriptionNotFound: "Подписка на этот канал не найдена.",
+	ErrMsgBundleExists:         "Подборка с таким названием уже существует.",
+	ErrMsgBundleNotFound:       "Подборка с таким названием не найдена.",
+	ErrMsgSubscriptionExists:   "Вы уже получаете уведомления о новостях из этого канала в данное время.",
+
+	ErrMsgNoPosts:           "За указанный период в канале не найдено постов.",
+	ErrMsgInviteRequestSent: "Канал принимает участников по заявке. Заявка отправлена, повторите запрос после её одобрения.",
+	ErrMsgInviteNotFound:    "Не удалось найти канал по этой ссылке. Проверьте её и повторите запрос.",
+	ErrMsgFloodWait:         "Telegram временно ограничил запросы. Попробуйте снова через %d мин.",
+
+	ErrMsgFeedNoPosts:     "За указанный период в ленте не найдено записей.",
+	ErrMsgFeedUnavailable: "Не удалось загрузить ленту по этому адресу. Проверьте ссылку и повторите запрос.",
+	ErrMsgFeedFormat:      "По этому адресу нет RSS- или Atom-ленты.",
+}
@@ -0,0 +1,82 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+func TestPlural(t *testing.T) {
+
+	tests := []struct {
+		language entity.Language
+		n        int
+		want     string
+	}{
+		{entity.LanguageRussian, 0, "0 постов"},
+		{entity.LanguageRussian, 1, "1 пост"},
+		{entity.LanguageRussian, 2, "2 поста"},
+		{entity.LanguageRussian, 4, "4 поста"},
+		{entity.LanguageRussian, 5, "5 постов"},
+		{entity.LanguageRussian, 11, "11 постов"},
+		{entity.LanguageRussian, 12, "12 постов"},
+		{entity.LanguageRussian, 14, "14 постов"},
+		{entity.LanguageRussian, 21, "21 пост"},
+		{entity.LanguageRussian, 22, "22 поста"},
+		{entity.LanguageRussian, 111, "111 постов"},
+		{entity.LanguageRussian, 101, "101 пост"},
+		{entity.LanguageEnglish, 0, "0 posts"},
+		{entity.LanguageEnglish, 1, "1 post"},
+		{entity.LanguageEnglish, 21, "21 posts"},
+		{entity.LanguageGerman, 1, "1 Beitrag"},
+		{entity.LanguageGerman, 2, "2 Beiträge"},
+	}
+
+	for _, tt := range tests {
+		if got := Plural(tt.language, tt.n, PluralPosts); got != tt.want {
+			t.Errorf("Plural(%s, %d) = %q, want %q", tt.language, tt.n, got, tt.want)
+		}
+	}
+}
+
+// Every catalog carries every message with the same format verbs, so no
+// language falls back silently or renders broken arguments.
+func TestCatalogsComplete(t *testing.T) {
+
+	reference := catalogs[entity.DefaultLanguage]
+
+	for language, catalog := range catalogs {
+
+		for id, text := range reference {
+
+			translated, ok := catalog[id]
+			if !ok {
+				t.Errorf("%s catalog misses %s", language, id)
+				continue
+			}
+
+			if verbs(translated) != verbs(text) {
+				t.Errorf("%s %s has verbs %q, want %q", language, id, verbs(translated), verbs(text))
+			}
+		}
+
+		for id := range catalog {
+			if _, ok := reference[id]; !ok {
+				t.Errorf("%s catalog has %s missing from the default one", language, id)
+			}
+		}
+	}
+}
+
+func verbs(text string) string {
+
+	var found []string
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] == '%' {
+			found = append(found, text[i:i+2])
+			i++
+		}
+	}
+	return strings.Join(found, " ")
+}
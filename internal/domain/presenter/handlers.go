@@ -2,43 +2,174 @@ package presenter
 
 import (
 	"errors"
+	"math"
+	"post-analyzer/internal/adapters/feed"
+	"post-analyzer/internal/adapters/telegram/user"
+	"post-analyzer/internal/domain/entity"
 	"post-analyzer/internal/domain/validation"
+	"post-analyzer/internal/infrastructure/repository"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-var validationHandler = func(e error) (string, bool) {
+var validationHandler = func(e error) (Message, bool) {
 
 	switch {
 
 	case errors.Is(e, validation.ErrArgNumber):
-		return "В бот нужно передать 2 опции: канал и время отправки сообщения.", true
+		return message(ErrMsgArgNumber), true
 
-	case errors.Is(e, validation.ErrTimeFormat):
-		return "Время отправки сообщения принимается в формате ЧЧ:ММ.", true
+	case errors.Is(e, validation.ErrChannelArgNumber):
+		return message(ErrMsgChannelArgNumber), true
 
-	case errors.Is(e, validation.ErrTimeValue):
-		return "Некорректное время отправки сообщения.", true
+	case errors.Is(e, validation.ErrOptionalArgNumber):
+		return message(ErrMsgOptionalArgNumber), true
+
+	case errors.Is(e, validation.ErrEditArgNumber):
+		return message(ErrMsgEditArgNumber), true
+
+	case errors.Is(e, validation.ErrBundleArgNumber):
+		return message(ErrMsgBundleArgNumber), true
+
+	case errors.Is(e, validation.ErrBundleName):
+		return message(ErrMsgBundleName), true
+
+	case errors.Is(e, validation.ErrOptionsArgNumber):
+		return message(ErrMsgOptionsArgNumber), true
+
+	case errors.Is(e, entity.ErrUnknownOption):
+		return message(ErrMsgUnknownOption), true
+
+	case errors.Is(e, entity.ErrOptionValue):
+		return message(ErrMsgOptionValue), true
+
+	case errors.Is(e, validation.ErrModelArgNumber):
+		return message(ErrMsgModelArgNumber), true
+
+	case errors.Is(e, validation.ErrPromptArgNumber):
+		return message(ErrMsgPromptArgNumber), true
+
+	case errors.Is(e, entity.ErrUnknownModelOption):
+		return message(ErrMsgUnknownModelOpt), true
+
+	case errors.Is(e, entity.ErrModelOptionValue):
+		return message(ErrMsgModelOptionValue, entity.MaxTemperature), true
+
+	case errors.Is(e, validation.ErrModelNotAllowed):
+		return message(ErrMsgModelNotAllowed), true
+
+	case errors.Is(e, entity.ErrPromptLength):
+		return message(ErrMsgPromptLength, entity.MaxPromptLength), true
+
+	case errors.Is(e, validation.ErrSingleArgNumber):
+		return message(ErrMsgSingleArgNumber), true
+
+	case errors.Is(e, validation.ErrUnknownTimezone):
+		return message(ErrMsgUnknownTimezone), true
+
+	case errors.Is(e, validation.ErrUnknownLanguage):
+		return message(ErrMsgUnknownLanguage, languageCodes()), true
+
+	case errors.Is(e, entity.ErrInvalidSchedule):
+		return message(ErrMsgInvalidSchedule), true
+
+	case errors.Is(e, entity.ErrScheduleTooFrequent):
+		return message(ErrMsgScheduleFrequent, int(entity.MinScheduleInterval.Minutes())), true
+
+	case errors.Is(e, validation.ErrHoursFormat):
+		return message(ErrMsgHoursFormat), true
+
+	case errors.Is(e, validation.ErrHoursValue):
+		return message(ErrMsgHoursValue, validation.MaxDigestHours), true
 
 	case errors.Is(e, validation.ErrShortUsername):
-		return "Слишком короткий юзернейм для канала. Проверьте его и отправьте запрос заново.", true
+		return message(ErrMsgShortUsername), true
 
 	case errors.Is(e, validation.ErrCharactersInName):
-		return "Юзернейм содержит некорректные символы. Проверьте его и отправьте запрос заново.", true
+		return message(ErrMsgNameCharacters), true
 
 	case errors.Is(e, validation.ErrChannelNotFound):
-		return "Не удалось найти канал с заданным юзернеймом. Проверьте его и повторите запрос.", true
+		return message(ErrMsgChannelNotFound), true
+
+	case errors.Is(e, validation.ErrInviteLink):
+		return message(ErrMsgInviteLink), true
+
+	case errors.Is(e, validation.ErrFeedURL):
+		return message(ErrMsgFeedURL), true
 	}
 
-	return "", false
+	return Message{}, false
 }
 
-var repositoryHandler = func(e error) (string, bool) {
+var repositoryHandler = func(e error) (Message, bool) {
+
+	switch {
+
+	case errors.Is(e, repository.ErrSubscriptionNotFound):
+		return message(ErrMsgSubscriptionNotFound), true
+
+	case errors.Is(e, repository.ErrBundleExists):
+		return message(ErrMsgBundleExists), true
+
+	case errors.Is(e, repository.ErrBundleNotFound):
+		return message(ErrMsgBundleNotFound), true
+	}
 
 	var pgErr *pgconn.PgError
 	if errors.As(e, &pgErr) && pgErr.Code == "23505" {
-		return "Вы уже получаете уведомления о новостях из этого канала в данное время.", true
+		return message(ErrMsgSubscriptionExists), true
+	}
+
+	return Message{}, false
+}
+
+var telegramHandler = func(e error) (Message, bool) {
+
+	switch {
+
+	case errors.Is(e, user.ErrNoPosts):
+		return message(ErrMsgNoPosts), true
+
+	case errors.Is(e, user.ErrInviteRequestSent):
+		return message(ErrMsgInviteRequestSent), true
+
+	case errors.Is(e, user.ErrChannelNotFound):
+		return message(ErrMsgInviteNotFound), true
+	}
+
+	var floodErr *user.FloodWaitError
+	if errors.As(e, &floodErr) {
+		minutes := int(math.Ceil(floodErr.Wait.Minutes()))
+		return message(ErrMsgFloodWait, minutes), true
+	}
+
+	return Message{}, false
+}
+
+var feedHandler = func(e error) (Message, bool) {
+
+	switch {
+
+	case errors.Is(e, feed.ErrNoPosts):
+		return message(ErrMsgFeedNoPosts), true
+
+	case errors.Is(e, feed.ErrFeedUnavailable):
+		return message(ErrMsgFeedUnavailable), true
+
+	case errors.Is(e, feed.ErrFeedFormat):
+		return message(ErrMsgFeedFormat), true
+	}
+
+	return Message{}, false
+}
+
+func languageCodes() string {
+
+	codes := make([]string, 0, len(entity.Languages))
+	for _, language := range entity.Languages {
+		codes = append(codes, string(language))
 	}
 
-	return "", false
+	return strings.Join(codes, ", ")
 }
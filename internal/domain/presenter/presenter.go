@@ -9,6 +9,8 @@ func register(h handler) {
 func init() {
 	register(validationHandler)
 	register(repositoryHandler)
+	register(telegramHandler)
+	register(feedHandler)
 }
 
 func PresentError(e error) *PresentedError {
@@ -16,12 +18,12 @@ func PresentError(e error) *PresentedError {
 	for _, h := range handlers {
 		if msg, ok := h(e); ok {
 			return &PresentedError{
-				UserMessage: msg,
+				Message: msg,
 			}
 		}
 	}
 
 	return &PresentedError{
-		UserMessage: "Внутрення ошибка сервиса. Пожалуйста, повторите запрос позже.",
+		Message: message(ErrMsgInternal),
 	}
 }
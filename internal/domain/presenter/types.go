@@ -1,11 +1,18 @@
 package presenter
 
-type handler func(error) (string, bool)
+import "post-analyzer/internal/domain/entity"
+
+type handler func(error) (Message, bool)
 
 type PresentedError struct {
-	UserMessage string
+	Message Message
+}
+
+// Text renders the user message in the language of the chat.
+func (p PresentedError) Text(language entity.Language) string {
+	return Text(language, p.Message.ID, p.Message.Args...)
 }
 
 func (p PresentedError) Error() string {
-	return p.UserMessage
+	return p.Text(entity.DefaultLanguage)
 }
@@ -4,17 +4,43 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/gotd/td/tg"
+	"net/url"
+	"post-analyzer/internal/adapters/feed"
 	"post-analyzer/internal/adapters/telegram/user"
 	"post-analyzer/internal/domain/entity"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const MaxDigestHours = 72
+
 var (
-	ErrArgNumber = errors.New("invalid number of arguments")
+	ErrArgNumber         = errors.New("invalid number of arguments")
+	ErrChannelArgNumber  = errors.New("invalid number of channel arguments")
+	ErrOptionalArgNumber = errors.New("invalid number of optional arguments")
+	ErrEditArgNumber     = errors.New("invalid number of edit arguments")
+	ErrSingleArgNumber   = errors.New("invalid number of single arguments")
+	ErrOptionsArgNumber  = errors.New("invalid number of options arguments")
+	ErrModelArgNumber    = errors.New("invalid number of model arguments")
+	ErrPromptArgNumber   = errors.New("invalid number of prompt arguments")
+
+	ErrModelNotAllowed = errors.New("model not allowed")
+
+	ErrUnknownTimezone = errors.New("unknown timezone")
+	ErrUnknownLanguage = errors.New("unknown language")
 
-	ErrTimeFormat = errors.New("invalid time format")
-	ErrTimeValue  = errors.New("invalid time value")
+	ErrHoursFormat = errors.New("invalid hours format")
+	ErrHoursValue  = errors.New("invalid hours value")
+
+	ErrBundleArgNumber = errors.New("invalid number of bundle arguments")
+	ErrBundleName      = errors.New("invalid bundle name")
+
+	ErrInviteLink = errors.New("invalid invite link")
+	ErrFeedURL    = errors.New("invalid feed url")
 
 	ErrShortUsername    = errors.New("channel username too short")
 	ErrCharactersInName = errors.New("forrbidden characters in username")
@@ -23,13 +49,20 @@ var (
 	ErrExternal        = errors.New("external service error")
 )
 
+var (
+	bundleNamePattern = regexp.MustCompile(`^[\p{L}0-9_-]{1,32}$`)
+	inviteHashPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
 type Validator func(ctx context.Context, command string, sub *entity.Subscription) error
 
+type BundleValidator func(ctx context.Context, command string, bundle *entity.Bundle) error
+
 func ArgsValidator(next Validator) Validator {
 	return func(ctx context.Context, command string, sub *entity.Subscription) error {
 
 		command = strings.TrimSpace(command)
-		if len(strings.Split(command, " ")) != 2 {
+		if len(strings.Fields(command)) < 2 {
 			return ErrArgNumber
 		}
 
@@ -40,22 +73,320 @@ func ArgsValidator(next Validator) Validator {
 	}
 }
 
-func TimeValidator(next Validator) Validator {
+func ChannelArgsValidator(next Validator) Validator {
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
 
+		command = strings.TrimSpace(command)
+		if command == "" {
+			return ErrChannelArgNumber
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func OptionalChannelValidator(next Validator) Validator {
 	return func(ctx context.Context, command string, sub *entity.Subscription) error {
 
-		time := strings.Split(command, " ")[1]
+		command = strings.TrimSpace(command)
+		if command == "" {
+			return nil
+		}
+
+		if len(strings.Split(command, " ")) != 1 {
+			return ErrSingleArgNumber
+		}
 
-		var hour, minute int
-		if _, err := fmt.Sscanf(time, "%d:%d", &hour, &minute); err != nil {
-			return ErrTimeFormat
+		if next != nil {
+			return next(ctx, command, sub)
 		}
+		return nil
+	}
+}
 
-		if (hour < 0 || hour > 23) || (minute < 0 || minute > 59) {
-			return ErrTimeValue
+func OptionalArgsValidator(next Validator) Validator {
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		command = strings.TrimSpace(command)
+		if command == "" || len(strings.Split(command, " ")) > 2 {
+			return ErrOptionalArgNumber
 		}
 
-		sub.SendingTime = time
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func EditArgsValidator(next Validator) Validator {
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		command = strings.TrimSpace(command)
+		if _, _, ok := editSchedules(command); !ok {
+			return ErrEditArgNumber
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func OptionsArgsValidator(next Validator) Validator {
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		command = strings.TrimSpace(command)
+		if len(strings.Fields(command)) < 2 {
+			return ErrOptionsArgNumber
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func SingleArgValidator(next Validator) Validator {
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		command = strings.TrimSpace(command)
+		if command == "" || len(strings.Split(command, " ")) != 1 {
+			return ErrSingleArgNumber
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func ScheduleValidator(next Validator) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		_, raw, _ := strings.Cut(command, " ")
+
+		schedule, err := entity.ParseSchedule(raw)
+		if err != nil {
+			return err
+		}
+
+		sub.Schedule = schedule
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func OptionalScheduleValidator(next Validator) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		if _, raw, found := strings.Cut(command, " "); found {
+
+			schedule, err := entity.ParseSchedule(raw)
+			if err != nil {
+				return err
+			}
+
+			sub.Schedule = schedule
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func EditScheduleValidator(next Validator, newSchedule *entity.Schedule) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		oldRaw, newRaw, _ := editSchedules(command)
+
+		schedule, err := entity.ParseSchedule(oldRaw)
+		if err != nil {
+			return err
+		}
+
+		if *newSchedule, err = entity.ParseSchedule(newRaw); err != nil {
+			return err
+		}
+
+		sub.Schedule = schedule
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func TimezoneValidator(next Validator) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		name := strings.Split(command, " ")[0]
+
+		// empty name and "Local" are accepted by time.LoadLocation
+		// but depend on the server, so they are rejected explicitly
+		if name == "" || name == "Local" {
+			return ErrUnknownTimezone
+		}
+
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return ErrUnknownTimezone
+		}
+
+		sub.Timezone = location.String()
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+// LanguageValidator accepts one of the supported language codes; the
+// subscription has no language of its own, so it is passed out.
+func LanguageValidator(next Validator, language *entity.Language) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		parsed, ok := entity.ParseLanguage(strings.Split(command, " ")[0])
+		if !ok {
+			return ErrUnknownLanguage
+		}
+
+		*language = parsed
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+// ReadOptionsValidator checks every "key=value" after the channel and
+// collects them, so they can be applied on top of each stored subscription.
+func ReadOptionsValidator(next Validator, options *[]string) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		for _, option := range strings.Fields(command)[1:] {
+
+			var check entity.ReadOptions
+			if err := check.Set(option); err != nil {
+				return err
+			}
+
+			*options = append(*options, option)
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+// ModelOptionsValidator checks every "key=value" and collects them, models
+// are limited to the allowed ones. Options may follow a channel, next then
+// resolves it, chat-wide options skip next.
+func ModelOptionsValidator(next Validator, options *[]string, allowed []string) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		fields := strings.Fields(command)
+
+		channel := ""
+		if len(fields) > 0 && isChannelReference(fields[0]) {
+			channel, fields = fields[0], fields[1:]
+		}
+
+		if len(fields) == 0 {
+			return ErrModelArgNumber
+		}
+
+		for _, option := range fields {
+
+			var check entity.AnalysisOptions
+			if err := check.Set(option); err != nil {
+				return err
+			}
+			if check.Model != "" && !slices.Contains(allowed, check.Model) {
+				return fmt.Errorf("%w: %s", ErrModelNotAllowed, check.Model)
+			}
+
+			*options = append(*options, option)
+		}
+
+		if channel != "" && next != nil {
+			return next(ctx, channel, sub)
+		}
+		return nil
+	}
+}
+
+// PromptValidator takes the rest of the command as the prompt, keeping its
+// line breaks. Like ModelOptionsValidator, next runs only for a channel.
+func PromptValidator(next Validator, prompt *string) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		command = strings.TrimSpace(command)
+
+		channel := ""
+		if fields := strings.Fields(command); len(fields) > 0 && isChannelReference(fields[0]) {
+			channel = fields[0]
+			command = strings.TrimSpace(strings.TrimPrefix(command, channel))
+		}
+
+		if command == "" {
+			return ErrPromptArgNumber
+		}
+
+		var check entity.AnalysisOptions
+		if err := check.SetPrompt(command); err != nil {
+			return err
+		}
+		*prompt = command
+
+		if channel != "" && next != nil {
+			return next(ctx, channel, sub)
+		}
+		return nil
+	}
+}
+
+func HoursValidator(next Validator, hours *int) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		if args := strings.Split(command, " "); len(args) > 1 {
+
+			value, err := strconv.Atoi(args[1])
+			if err != nil {
+				return ErrHoursFormat
+			}
+
+			if value < 1 || value > MaxDigestHours {
+				return ErrHoursValue
+			}
+
+			*hours = value
+		}
 
 		if next != nil {
 			return next(ctx, command, sub)
@@ -70,6 +401,38 @@ func ChannelNameValidator(next Validator) Validator {
 
 		name := strings.Split(command, " ")[0]
 
+		if hash, ok := inviteHash(name); ok {
+
+			if !inviteHashPattern.MatchString(hash) {
+				return ErrInviteLink
+			}
+
+			sub.InviteHash = hash
+			sub.ChannelUsername = ""
+
+			if next != nil {
+				return next(ctx, command, sub)
+			}
+			return nil
+		}
+
+		// feeds are known by their URL, so the ID is derived without a fetch
+		if feedURL, ok := feedReference(name); ok {
+
+			if feedURL == "" {
+				return ErrFeedURL
+			}
+
+			sub.FeedURL = feedURL
+			sub.ChannelID = entity.FeedChannelID(feedURL)
+			sub.ChannelUsername = ""
+
+			if next != nil {
+				return next(ctx, command, sub)
+			}
+			return nil
+		}
+
 		name = strings.TrimPrefix(name, "https://")
 		name = strings.TrimPrefix(name, "http://")
 		name = strings.TrimPrefix(name, "t.me/")
@@ -95,10 +458,56 @@ func ChannelNameValidator(next Validator) Validator {
 	}
 }
 
-func ChannelValidator(next Validator, client user.TelegramService) Validator {
+// ChannelValidator resolves the channel, joining it when it is named by an
+// invite link.
+func ChannelValidator(next Validator, client user.TelegramService, feeds feed.FeedService) Validator {
+	return channelValidator(next, client, feeds, client.JoinChannel)
+}
+
+// ChannelCheckValidator resolves the channel like ChannelValidator, but only
+// looks at invite links, so checking a channel joins nothing.
+func ChannelCheckValidator(next Validator, client user.TelegramService, feeds feed.FeedService) Validator {
+	return channelValidator(next, client, feeds, client.PreviewInvite)
+}
+
+func channelValidator(next Validator, client user.TelegramService, feeds feed.FeedService,
+	invite func(ctx context.Context, hash string) (*tg.Channel, error)) Validator {
 
 	return func(ctx context.Context, command string, sub *entity.Subscription) error {
 
+		if sub.FeedURL != "" {
+
+			channel, err := feeds.ResolveFeed(ctx, sub.FeedURL)
+			if err != nil {
+				return feedError(err)
+			}
+
+			sub.ChannelID = channel.ID
+			sub.ChannelTitle = channel.Title
+
+			if next != nil {
+				return next(ctx, command, sub)
+			}
+			return nil
+		}
+
+		if sub.InviteHash != "" {
+
+			channel, err := invite(ctx, sub.InviteHash)
+			if err != nil {
+				return inviteError(err)
+			}
+
+			sub.ChannelID = channel.ID
+			sub.ChannelUsername = channel.Username
+			sub.ChannelTitle = channel.Title
+
+			if next != nil {
+				return next(ctx, command, sub)
+			}
+			return nil
+		}
+
 		channel, err := client.ChannelInfo(ctx, sub.ChannelUsername)
 		if err != nil {
 			if errors.Is(err, user.ErrChannelNotFound) {
@@ -108,6 +517,7 @@ func ChannelValidator(next Validator, client user.TelegramService) Validator {
 		}
 
 		sub.ChannelID = channel.ID
+		sub.ChannelTitle = channel.Title
 
 		if next != nil {
 			return next(ctx, command, sub)
@@ -116,3 +526,240 @@ func ChannelValidator(next Validator, client user.TelegramService) Validator {
 	}
 
 }
+
+// InviteValidator resolves an invite link to an already joined channel, so
+// commands can match subscriptions to private channels by their ID.
+func InviteValidator(next Validator, client user.TelegramService) Validator {
+
+	return func(ctx context.Context, command string, sub *entity.Subscription) error {
+
+		if sub.InviteHash != "" {
+
+			channel, err := client.CheckInvite(ctx, sub.InviteHash)
+			if err != nil {
+				return inviteError(err)
+			}
+
+			sub.ChannelID = channel.ID
+			sub.ChannelUsername = channel.Username
+		}
+
+		if next != nil {
+			return next(ctx, command, sub)
+		}
+		return nil
+	}
+}
+
+func inviteError(err error) error {
+
+	switch {
+	case errors.Is(err, user.ErrInviteInvalid), errors.Is(err, user.ErrNotChannel):
+		return fmt.Errorf("%w: %s", ErrInviteLink, err)
+	case errors.Is(err, user.ErrInviteRequestSent), errors.Is(err, user.ErrChannelNotFound):
+		return err
+	}
+
+	return ErrExternal
+}
+
+func feedError(err error) error {
+
+	switch {
+	case errors.Is(err, feed.ErrFeedUnavailable), errors.Is(err, feed.ErrFeedFormat):
+		return err
+	}
+
+	return ErrExternal
+}
+
+// feedReference tells web addresses apart from Telegram links. An empty URL
+// with ok set means the reference looks like a feed but cannot be one.
+func feedReference(reference string) (string, bool) {
+
+	if !strings.HasPrefix(reference, "http://") && !strings.HasPrefix(reference, "https://") {
+		return "", false
+	}
+
+	parsed, err := url.Parse(reference)
+	if err != nil {
+		return "", true
+	}
+
+	switch strings.ToLower(parsed.Hostname()) {
+	case "t.me", "telegram.me":
+		return "", false
+	case "":
+		return "", true
+	}
+
+	return parsed.String(), true
+}
+
+// inviteHash extracts the hash from t.me/+HASH, t.me/joinchat/HASH
+// and tg://join?invite=HASH links.
+func inviteHash(reference string) (string, bool) {
+
+	if hash, found := strings.CutPrefix(reference, "tg://join?invite="); found {
+		return hash, true
+	}
+
+	reference = strings.TrimPrefix(reference, "https://")
+	reference = strings.TrimPrefix(reference, "http://")
+
+	for _, host := range []string{"t.me/", "telegram.me/"} {
+
+		path, found := strings.CutPrefix(reference, host)
+		if !found {
+			continue
+		}
+
+		if hash, found := strings.CutPrefix(path, "+"); found {
+			return hash, true
+		}
+		if hash, found := strings.CutPrefix(path, "joinchat/"); found {
+			return hash, true
+		}
+	}
+
+	return "", false
+}
+
+// editSchedules splits "channel OLD -> NEW" or the short "channel OLD NEW" form.
+func editSchedules(command string) (string, string, bool) {
+
+	_, rest, found := strings.Cut(command, " ")
+	if !found {
+		return "", "", false
+	}
+
+	if oldRaw, newRaw, found := strings.Cut(rest, "->"); found {
+		oldRaw, newRaw = strings.TrimSpace(oldRaw), strings.TrimSpace(newRaw)
+		return oldRaw, newRaw, oldRaw != "" && newRaw != ""
+	}
+
+	parts := strings.Fields(rest)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+func BundleArgsValidator(next BundleValidator) BundleValidator {
+	return func(ctx context.Context, command string, bundle *entity.Bundle) error {
+
+		command = strings.TrimSpace(command)
+		if _, channels, schedule := bundleParts(command); len(channels) < 2 || schedule == "" {
+			return ErrBundleArgNumber
+		}
+
+		if next != nil {
+			return next(ctx, command, bundle)
+		}
+		return nil
+	}
+}
+
+func BundleNameValidator(next BundleValidator) BundleValidator {
+
+	return func(ctx context.Context, command string, bundle *entity.Bundle) error {
+
+		name, _, _ := bundleParts(command)
+
+		if !bundleNamePattern.MatchString(name) {
+			return ErrBundleName
+		}
+
+		bundle.Name = strings.ToLower(name)
+
+		if next != nil {
+			return next(ctx, command, bundle)
+		}
+		return nil
+	}
+}
+
+func BundleScheduleValidator(next BundleValidator) BundleValidator {
+
+	return func(ctx context.Context, command string, bundle *entity.Bundle) error {
+
+		_, _, raw := bundleParts(command)
+
+		schedule, err := entity.ParseSchedule(raw)
+		if err != nil {
+			return err
+		}
+
+		bundle.Schedule = schedule
+
+		if next != nil {
+			return next(ctx, command, bundle)
+		}
+		return nil
+	}
+}
+
+func BundleChannelsValidator(next BundleValidator, client user.TelegramService, feeds feed.FeedService) BundleValidator {
+
+	return func(ctx context.Context, command string, bundle *entity.Bundle) error {
+
+		_, channels, _ := bundleParts(command)
+
+		// every member goes through the same checks as a single subscription
+		channelChain := ChannelNameValidator(
+			ChannelValidator(nil, client, feeds),
+		)
+
+		seen := make(map[int64]bool)
+		for _, channel := range channels {
+
+			var sub entity.Subscription
+			if err := channelChain(ctx, channel, &sub); err != nil {
+				return err
+			}
+
+			if seen[sub.ChannelID] {
+				continue
+			}
+			seen[sub.ChannelID] = true
+
+			bundle.Channels = append(bundle.Channels, &entity.BundleChannel{
+				ChannelID:         sub.ChannelID,
+				ChannelUsername:   sub.ChannelUsername,
+				ChannelTitle:      sub.ChannelTitle,
+				LastCheckedPostID: -1,
+			})
+		}
+
+		if next != nil {
+			return next(ctx, command, bundle)
+		}
+		return nil
+	}
+}
+
+// bundleParts splits "name @a @b t.me/c SCHEDULE" into its name,
+// channel references and the remaining schedule.
+func bundleParts(command string) (string, []string, string) {
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", nil, ""
+	}
+
+	var channels []string
+	rest := fields[1:]
+	for len(rest) > 0 && isChannelReference(rest[0]) {
+		channels = append(channels, rest[0])
+		rest = rest[1:]
+	}
+
+	return fields[0], channels, strings.Join(rest, " ")
+}
+
+func isChannelReference(field string) bool {
+	return strings.HasPrefix(field, "@") || strings.HasPrefix(field, "tg://join") ||
+		strings.Contains(field, "t.me/") || strings.Contains(field, "telegram.me/") ||
+		strings.HasPrefix(field, "http://") || strings.HasPrefix(field, "https://")
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+func TestModelOptionsValidator(t *testing.T) {
+
+	allowed := []string{"openai/gpt-4o-mini", "deepseek/deepseek-chat-v3.1:free"}
+
+	tests := []struct {
+		name    string
+		command string
+		options []string
+		next    bool
+		wantErr error
+	}{
+		{"allowed model", "model=openai/gpt-4o-mini", []string{"model=openai/gpt-4o-mini"}, false, nil},
+		{"default model", "model=default temperature=0.3", []string{"model=default", "temperature=0.3"}, false, nil},
+		{"sampling only", "top_p=0.9", []string{"top_p=0.9"}, false, nil},
+		{"channel first", "@channel temperature=1", []string{"temperature=1"}, true, nil},
+		{"model not allowed", "model=anthropic/expensive", nil, false, ErrModelNotAllowed},
+		{"model case matters", "model=OpenAI/GPT-4o-mini", nil, false, ErrModelNotAllowed},
+		{"unknown option", "seed=1", nil, false, entity.ErrUnknownModelOption},
+		{"bad value", "temperature=5", nil, false, entity.ErrModelOptionValue},
+		{"no options", "", nil, false, ErrModelArgNumber},
+		{"channel only", "@channel", nil, false, ErrModelArgNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var options []string
+			called := false
+			next := func(ctx context.Context, command string, sub *entity.Subscription) error {
+				called = true
+				return nil
+			}
+
+			err := ModelOptionsValidator(next, &options, allowed)(context.Background(), tt.command, &entity.Subscription{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !slices.Equal(options, tt.options) {
+				t.Errorf("options = %v, want %v", options, tt.options)
+			}
+			if called != tt.next {
+				t.Errorf("next called = %v, want %v", called, tt.next)
+			}
+		})
+	}
+}
+
+func TestModelOptionsValidatorWithoutAllowedModels(t *testing.T) {
+
+	var options []string
+	validate := ModelOptionsValidator(nil, &options, nil)
+
+	if err := validate(context.Background(), "model=openai/gpt-4o-mini", &entity.Subscription{}); !errors.Is(err, ErrModelNotAllowed) {
+		t.Errorf("error = %v, want %v", err, ErrModelNotAllowed)
+	}
+	if err := validate(context.Background(), "model=default", &entity.Subscription{}); err != nil {
+		t.Errorf("error = %v, want the default model accepted", err)
+	}
+}
+
+func TestChannelNameValidator(t *testing.T) {
+
+	tests := []struct {
+		command  string
+		username string
+		invite   string
+		feedURL  string
+		wantErr  error
+	}{
+		{command: "@durov", username: "durov"},
+		{command: "durov_news 09:00", username: "durov_news"},
+		{command: "https://t.me/durov/123", username: "durov"},
+		{command: "t.me/durov?start=1", username: "durov"},
+		{command: "telegram.me/durov", username: "durov"},
+		{command: "https://t.me/+AbC_12-x", invite: "AbC_12-x"},
+		{command: "t.me/joinchat/AbC123", invite: "AbC123"},
+		{command: "tg://join?invite=AbC123", invite: "AbC123"},
+		{command: "https://example.com/feed.xml", feedURL: "https://example.com/feed.xml"},
+		{command: "http://example.com/rss?lang=en", feedURL: "http://example.com/rss?lang=en"},
+
+		{command: "@abc", wantErr: ErrShortUsername},
+		{command: "@durov!", wantErr: ErrCharactersInName},
+		{command: "@дуров_канал", wantErr: ErrCharactersInName},
+		{command: "t.me/+bad!hash", wantErr: ErrInviteLink},
+		{command: "https:///feed.xml", wantErr: ErrFeedURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+
+			sub := &entity.Subscription{}
+			err := ChannelNameValidator(nil)(context.Background(), tt.command, sub)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if sub.ChannelUsername != tt.username || sub.InviteHash != tt.invite || sub.FeedURL != tt.feedURL {
+				t.Errorf("got username %q, invite %q, feed %q; want %q, %q, %q",
+					sub.ChannelUsername, sub.InviteHash, sub.FeedURL, tt.username, tt.invite, tt.feedURL)
+			}
+			if tt.feedURL != "" && sub.ChannelID != entity.FeedChannelID(tt.feedURL) {
+				t.Errorf("feed channel ID = %d, want %d", sub.ChannelID, entity.FeedChannelID(tt.feedURL))
+			}
+		})
+	}
+}
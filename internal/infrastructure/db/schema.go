@@ -13,11 +13,19 @@ const (
 		chat_id BIGINT NOT NULL,
 		channel_id BIGINT NOT NULL,
 		last_checked_id BIGINT DEFAULT -1,
-		send_time TIME NOT NULL,
+		schedule TEXT NOT NULL,
 		schedule_id INTEGER NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		topic_id INTEGER NOT NULL DEFAULT 0,
+		exclude_bots BOOLEAN NOT NULL DEFAULT FALSE,
+		include_comments BOOLEAN NOT NULL DEFAULT FALSE,
+		model TEXT NOT NULL DEFAULT '',
+		temperature REAL,
+		top_p REAL,
+		system_prompt TEXT NOT NULL DEFAULT '',
 		creation_data TIMESTAMPTZ DEFAULT NOW(),
 
-		UNIQUE(chat_id, channel_id, send_time),
+		UNIQUE(chat_id, channel_id, schedule),
 
 		CONSTRAINT fk_channel
 			FOREIGN KEY (channel_id)
@@ -29,8 +37,177 @@ const (
 	CREATE TABLE IF NOT EXISTS channel (
 		id BIGSERIAL PRIMARY KEY,
 		channel_id BIGINT UNIQUE NOT NULL,
-		username TEXT UNIQUE NOT NULL
+		username TEXT UNIQUE,
+		access_hash BIGINT NOT NULL DEFAULT 0,
+		title TEXT NOT NULL DEFAULT '',
+		about TEXT NOT NULL DEFAULT '',
+		participants INTEGER NOT NULL DEFAULT 0,
+		linked_chat_id BIGINT NOT NULL DEFAULT 0,
+		info_updated_at TIMESTAMPTZ,
+		feed_url TEXT
 	);`
+
+	// channels resolved before peers were cached have no access hash yet
+	addChannelAccessHash = `
+	ALTER TABLE channel ADD COLUMN IF NOT EXISTS access_hash BIGINT NOT NULL DEFAULT 0;
+	ALTER TABLE channel ALTER COLUMN username DROP NOT NULL;`
+
+	addChannelTitle = `
+	ALTER TABLE channel ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';`
+
+	addChannelInfo = `
+	ALTER TABLE channel
+		ADD COLUMN IF NOT EXISTS about TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS participants INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS linked_chat_id BIGINT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS info_updated_at TIMESTAMPTZ;`
+
+	addChannelFeedURL = `
+	ALTER TABLE channel ADD COLUMN IF NOT EXISTS feed_url TEXT;`
+
+	// subscriptions created before flexible schedules stored a bare send_time
+	migrateSubscriptionSchedule = `
+	DO $$
+	BEGIN
+		IF EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_name = 'subscription' AND column_name = 'send_time'
+		) THEN
+			ALTER TABLE subscription ADD COLUMN IF NOT EXISTS schedule TEXT;
+			UPDATE subscription SET schedule = to_char(send_time, 'HH24:MI') WHERE schedule IS NULL;
+			ALTER TABLE subscription DROP COLUMN send_time;
+			ALTER TABLE subscription ALTER COLUMN schedule SET NOT NULL;
+			ALTER TABLE subscription ADD UNIQUE (chat_id, channel_id, schedule);
+		END IF;
+	END $$;`
+
+	addSubscriptionActive = `
+	ALTER TABLE subscription ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;`
+
+	createBundleTable = `
+	CREATE TABLE IF NOT EXISTS bundle (
+		id BIGSERIAL PRIMARY KEY,
+		chat_id BIGINT NOT NULL,
+		name TEXT NOT NULL,
+		schedule TEXT NOT NULL,
+		schedule_id INTEGER NOT NULL,
+		creation_data TIMESTAMPTZ DEFAULT NOW(),
+
+		UNIQUE(chat_id, name)
+	);`
+
+	createBundleChannelTable = `
+	CREATE TABLE IF NOT EXISTS bundle_channel (
+		bundle_id BIGINT NOT NULL,
+		channel_id BIGINT NOT NULL,
+		last_checked_id BIGINT DEFAULT -1,
+
+		PRIMARY KEY(bundle_id, channel_id),
+
+		CONSTRAINT fk_bundle
+			FOREIGN KEY (bundle_id)
+			REFERENCES bundle(id)
+			ON DELETE CASCADE,
+
+		CONSTRAINT fk_channel
+			FOREIGN KEY (channel_id)
+			REFERENCES channel(channel_id)
+			ON DELETE CASCADE
+	);`
+
+	createPostTable = `
+	CREATE TABLE IF NOT EXISTS post (
+		channel_id BIGINT NOT NULL,
+		post_id BIGINT NOT NULL,
+		date TIMESTAMPTZ NOT NULL,
+		text TEXT NOT NULL,
+
+		PRIMARY KEY(channel_id, post_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS post_date_idx ON post(date);`
+
+	addPostDetails = `
+	ALTER TABLE post
+		ADD COLUMN IF NOT EXISTS grouped_id BIGINT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS caption TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS urls TEXT[] NOT NULL DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS forwarded_from TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS poll_question TEXT,
+		ADD COLUMN IF NOT EXISTS poll_options TEXT[],
+		ADD COLUMN IF NOT EXISTS views INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS forwards INTEGER NOT NULL DEFAULT 0;`
+
+	addPostReactions = `
+	ALTER TABLE post ADD COLUMN IF NOT EXISTS reactions INTEGER NOT NULL DEFAULT 0;`
+
+	// supergroup messages are filtered by topic and attributed to authors
+	addPostAuthor = `
+	ALTER TABLE post
+		ADD COLUMN IF NOT EXISTS topic_id INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS author TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS from_bot BOOLEAN NOT NULL DEFAULT FALSE;`
+
+	createSentPostTable = `
+	CREATE TABLE IF NOT EXISTS sent_post (
+		chat_id BIGINT NOT NULL,
+		channel_id BIGINT NOT NULL,
+		post_id BIGINT NOT NULL,
+		link TEXT NOT NULL,
+		excerpt TEXT NOT NULL,
+		sent_at TIMESTAMPTZ NOT NULL,
+
+		PRIMARY KEY(chat_id, channel_id, post_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS sent_post_sent_at_idx ON sent_post(sent_at);`
+
+	// feeds date entries loosely, so they are read in the order they were first seen
+	createFeedEntryTable = `
+	CREATE TABLE IF NOT EXISTS feed_entry (
+		channel_id BIGINT NOT NULL,
+		entry_id BIGINT NOT NULL,
+		first_seen TIMESTAMPTZ NOT NULL,
+
+		PRIMARY KEY(channel_id, entry_id)
+	);`
+
+	addSubscriptionReadOptions = `
+	ALTER TABLE subscription
+		ADD COLUMN IF NOT EXISTS topic_id INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS exclude_bots BOOLEAN NOT NULL DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS include_comments BOOLEAN NOT NULL DEFAULT FALSE;`
+
+	// unset model settings fall back to the chat defaults, then to the built-in ones
+	addSubscriptionAnalysisOptions = `
+	ALTER TABLE subscription
+		ADD COLUMN IF NOT EXISTS model TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS temperature REAL,
+		ADD COLUMN IF NOT EXISTS top_p REAL,
+		ADD COLUMN IF NOT EXISTS system_prompt TEXT NOT NULL DEFAULT '';`
+
+	createChatSettingsTable = `
+	CREATE TABLE IF NOT EXISTS chat_settings (
+		chat_id BIGINT PRIMARY KEY,
+		timezone TEXT NOT NULL DEFAULT '',
+		language TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		temperature REAL,
+		top_p REAL,
+		system_prompt TEXT NOT NULL DEFAULT ''
+	);`
+
+	addChatSettingsAnalysisOptions = `
+	ALTER TABLE chat_settings
+		ADD COLUMN IF NOT EXISTS model TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS temperature REAL,
+		ADD COLUMN IF NOT EXISTS top_p REAL,
+		ADD COLUMN IF NOT EXISTS system_prompt TEXT NOT NULL DEFAULT '';`
+
+	// empty language is filled from the Telegram client language on first contact
+	addChatSettingsLanguage = `
+	ALTER TABLE chat_settings
+		ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';`
 )
 
 func EnsureSchema(ctx context.Context, pool *pgxpool.Pool) error {
@@ -39,9 +216,85 @@ func EnsureSchema(ctx context.Context, pool *pgxpool.Pool) error {
 		return err
 	}
 
+	if _, err := pool.Exec(ctx, addChannelAccessHash); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addChannelTitle); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addChannelInfo); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addChannelFeedURL); err != nil {
+		return err
+	}
+
 	if _, err := pool.Exec(ctx, createSubscriptionTable); err != nil {
 		return err
 	}
 
+	if _, err := pool.Exec(ctx, migrateSubscriptionSchedule); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addSubscriptionActive); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addSubscriptionReadOptions); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addSubscriptionAnalysisOptions); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createChatSettingsTable); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addChatSettingsAnalysisOptions); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addChatSettingsLanguage); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createBundleTable); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createBundleChannelTable); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createPostTable); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addPostDetails); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addPostReactions); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, addPostAuthor); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createSentPostTable); err != nil {
+		return err
+	}
+
+	if _, err := pool.Exec(ctx, createFeedEntryTable); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"post-analyzer/internal/domain/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrBundleExists   = errors.New("bundle already exists")
+	ErrBundleNotFound = errors.New("bundle not found")
+)
+
+type BundleRepository interface {
+	AddBundle(context.Context, *entity.Bundle) error
+	GetBundles(context.Context, int64) ([]*entity.Bundle, error)
+	GetAllBundles(context.Context) ([]*entity.Bundle, error)
+	UpdateBundle(context.Context, *entity.Bundle) error
+	DeleteBundle(context.Context, *entity.Bundle) error
+}
+
+type bundleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBundleRepository(database *pgxpool.Pool) BundleRepository {
+	return &bundleRepository{
+		db: database,
+	}
+}
+
+func (r *bundleRepository) AddBundle(ctx context.Context, bundle *entity.Bundle) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, err)
+	}
+
+	defer func() {
+		if err != nil {
+			err := tx.Rollback(ctx)
+			if err != nil {
+				log.Printf("%v: %s", ErrRollbackFailed, err)
+			}
+		}
+	}()
+
+	err = tx.QueryRow(ctx,
+		`
+		INSERT INTO bundle(chat_id, name, schedule, schedule_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+		`,
+		bundle.ChatID, bundle.Name, bundle.Schedule.String(), bundle.ScheduleID).Scan(&bundle.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrBundleExists
+		}
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	for _, channel := range bundle.Channels {
+
+		if err = saveChannelName(ctx, tx, channel.ChannelID, channel.ChannelUsername, channel.ChannelTitle); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`
+			INSERT INTO bundle_channel(bundle_id, channel_id, last_checked_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+			`,
+			bundle.ID, channel.ChannelID, channel.LastCheckedPostID)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCommitFailed, err)
+	}
+
+	return nil
+}
+
+func (r *bundleRepository) GetBundles(ctx context.Context, chatID int64) ([]*entity.Bundle, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT b.id, b.chat_id, b.name, b.schedule, COALESCE(cs.timezone, ''), b.schedule_id,
+			bc.channel_id, COALESCE(c.username, ''), c.title, bc.last_checked_id
+		FROM bundle b
+			INNER JOIN bundle_channel bc ON bc.bundle_id = b.id
+			INNER JOIN channel c ON c.channel_id = bc.channel_id
+			LEFT JOIN chat_settings cs ON cs.chat_id = b.chat_id
+		WHERE b.chat_id = $1
+		ORDER BY b.name, c.username
+		`,
+		chatID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return scanBundles(rows)
+}
+
+func (r *bundleRepository) GetAllBundles(ctx context.Context) ([]*entity.Bundle, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT b.id, b.chat_id, b.name, b.schedule, COALESCE(cs.timezone, ''), b.schedule_id,
+			bc.channel_id, COALESCE(c.username, ''), c.title, bc.last_checked_id
+		FROM bundle b
+			INNER JOIN bundle_channel bc ON bc.bundle_id = b.id
+			INNER JOIN channel c ON c.channel_id = bc.channel_id
+			LEFT JOIN chat_settings cs ON cs.chat_id = b.chat_id
+		ORDER BY b.id, c.username
+		`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return scanBundles(rows)
+}
+
+func scanBundles(rows pgx.Rows) ([]*entity.Bundle, error) {
+
+	defer rows.Close()
+
+	var bundles []*entity.Bundle
+	byID := make(map[int64]*entity.Bundle)
+
+	for rows.Next() {
+
+		var bundle entity.Bundle
+		var schedule string
+		var channel entity.BundleChannel
+		err := rows.Scan(
+			&bundle.ID,
+			&bundle.ChatID,
+			&bundle.Name,
+			&schedule,
+			&bundle.Timezone,
+			&bundle.ScheduleID,
+			&channel.ChannelID,
+			&channel.ChannelUsername,
+			&channel.ChannelTitle,
+			&channel.LastCheckedPostID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+		}
+
+		existing, ok := byID[bundle.ID]
+		if !ok {
+			if bundle.Schedule, err = entity.ParseSchedule(schedule); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+			}
+
+			existing = &bundle
+			byID[bundle.ID] = existing
+			bundles = append(bundles, existing)
+		}
+
+		existing.Channels = append(existing.Channels, &channel)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingStreamFailed, err)
+	}
+
+	return bundles, nil
+}
+
+func (r *bundleRepository) UpdateBundle(ctx context.Context, bundle *entity.Bundle) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, err)
+	}
+
+	defer func() {
+		if err != nil {
+			err := tx.Rollback(ctx)
+			if err != nil {
+				log.Printf("%v: %s", ErrRollbackFailed, err)
+			}
+		}
+	}()
+
+	_, err = tx.Exec(ctx,
+		`
+		UPDATE bundle
+		SET schedule_id = $1
+		WHERE id = $2
+		`,
+		bundle.ScheduleID, bundle.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	for _, channel := range bundle.Channels {
+
+		_, err = tx.Exec(ctx,
+			`
+			UPDATE bundle_channel
+			SET last_checked_id = $1
+			WHERE bundle_id = $2 AND channel_id = $3
+			`,
+			channel.LastCheckedPostID, bundle.ID, channel.ChannelID)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCommitFailed, err)
+	}
+
+	return nil
+}
+
+func (r *bundleRepository) DeleteBundle(ctx context.Context, bundle *entity.Bundle) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		DELETE FROM bundle
+		WHERE id = $1
+		`,
+		bundle.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDeletingFailed, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrBundleNotFound
+	}
+
+	return nil
+}
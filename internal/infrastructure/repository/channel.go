@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"post-analyzer/internal/domain/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrChannelNotFound = errors.New("channel not found")
+
+type ChannelRepository interface {
+	GetChannel(context.Context, int64) (*entity.Channel, error)
+	SaveChannel(context.Context, *entity.Channel) error
+	SaveChannelInfo(context.Context, *entity.Channel) error
+}
+
+type channelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChannelRepository(database *pgxpool.Pool) ChannelRepository {
+	return &channelRepository{
+		db: database,
+	}
+}
+
+func (r *channelRepository) GetChannel(ctx context.Context, channelID int64) (*entity.Channel, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	channel := &entity.Channel{
+		ID: channelID,
+	}
+
+	var infoUpdatedAt *time.Time
+	err := r.db.QueryRow(ctx,
+		`
+		SELECT COALESCE(username, ''), access_hash, title, about, participants, linked_chat_id, info_updated_at,
+			COALESCE(feed_url, '')
+		FROM channel
+		WHERE channel_id = $1
+		`,
+		channelID).Scan(&channel.Username, &channel.AccessHash, &channel.Title,
+		&channel.About, &channel.Participants, &channel.LinkedChatID, &infoUpdatedAt, &channel.FeedURL)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrChannelNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	if infoUpdatedAt != nil {
+		channel.InfoUpdatedAt = *infoUpdatedAt
+	}
+
+	return channel, nil
+}
+
+func (r *channelRepository) SaveChannel(ctx context.Context, channel *entity.Channel) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, err)
+	}
+
+	defer func() {
+		if err != nil {
+			err := tx.Rollback(ctx)
+			if err != nil {
+				log.Printf("%v: %s", ErrRollbackFailed, err)
+			}
+		}
+	}()
+
+	if err = releaseUsername(ctx, tx, channel.ID, channel.Username); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`
+		INSERT INTO channel(channel_id, username, access_hash, title, feed_url)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''))
+		ON CONFLICT (channel_id)
+		DO UPDATE SET username = EXCLUDED.username, access_hash = EXCLUDED.access_hash, title = EXCLUDED.title,
+			feed_url = EXCLUDED.feed_url
+		`,
+		channel.ID, channel.Username, channel.AccessHash, channel.Title, channel.FeedURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCommitFailed, err)
+	}
+
+	return nil
+}
+
+// saveChannelName records the name of a subscribed channel, keeping the
+// peer details SaveChannel cached for it.
+func saveChannelName(ctx context.Context, tx pgx.Tx, channelID int64, username, title string) error {
+
+	if err := releaseUsername(ctx, tx, channelID, username); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx,
+		`
+		INSERT INTO channel(channel_id, username, title)
+		VALUES ($1, NULLIF($2, ''), $3)
+		ON CONFLICT (channel_id)
+		DO UPDATE SET username = EXCLUDED.username, title = EXCLUDED.title
+		`,
+		channelID, username, title)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	return nil
+}
+
+// releaseUsername clears the username from other channels. Usernames can
+// move between channels, the previous owner gets its new one back the next
+// time its history is fetched.
+func releaseUsername(ctx context.Context, tx pgx.Tx, channelID int64, username string) error {
+
+	_, err := tx.Exec(ctx,
+		`
+		UPDATE channel
+		SET username = NULL
+		WHERE username = $2 AND channel_id <> $1
+		`,
+		channelID, username)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// SaveChannelInfo stores the full channel info of an already saved channel.
+func (r *channelRepository) SaveChannelInfo(ctx context.Context, channel *entity.Channel) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		UPDATE channel
+		SET about = $2, participants = $3, linked_chat_id = $4, info_updated_at = $5
+		WHERE channel_id = $1
+		`,
+		channel.ID, channel.About, channel.Participants, channel.LinkedChatID, channel.InfoUpdatedAt)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// feedEntryRetention keeps entries that left the feed for a while, so
+// an entry coming back to the feed is not read as a new one.
+const feedEntryRetention = 30 * 24 * time.Hour
+
+type FeedEntryRepository interface {
+	SeeEntries(context.Context, int64, []int64, time.Time) (map[int64]time.Time, error)
+}
+
+type feedEntryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewFeedEntryRepository(database *pgxpool.Pool) FeedEntryRepository {
+	return &feedEntryRepository{
+		db: database,
+	}
+}
+
+// SeeEntries records the entries currently in the feed, the new ones as
+// seen at seenAt, and returns when each of them was first seen.
+func (r *feedEntryRepository) SeeEntries(ctx context.Context, channelID int64, entryIDs []int64, seenAt time.Time) (map[int64]time.Time, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(
+		`
+		INSERT INTO feed_entry(channel_id, entry_id, first_seen)
+		SELECT $1, UNNEST($2::BIGINT[]), $3
+		ON CONFLICT (channel_id, entry_id) DO NOTHING
+		`,
+		channelID, entryIDs, seenAt)
+	batch.Queue(
+		`
+		DELETE FROM feed_entry
+		WHERE channel_id = $1 AND NOT (entry_id = ANY($2)) AND first_seen < $3
+		`,
+		channelID, entryIDs, seenAt.Add(-feedEntryRetention))
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT entry_id, first_seen
+		FROM feed_entry
+		WHERE channel_id = $1 AND entry_id = ANY($2)
+		`,
+		channelID, entryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]time.Time, len(entryIDs))
+	for rows.Next() {
+
+		var id int64
+		var firstSeen time.Time
+		if err := rows.Scan(&id, &firstSeen); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+		}
+
+		seen[id] = firstSeen
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingStreamFailed, err)
+	}
+
+	return seen, nil
+}
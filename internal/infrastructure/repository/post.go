@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"post-analyzer/internal/domain/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostRepository interface {
+	SavePosts(context.Context, []*entity.Post) error
+	GetPosts(context.Context, int64, int64, time.Time, int) ([]*entity.Post, error)
+	LatestPostID(context.Context, int64) (int64, error)
+	DeletePosts(context.Context, int64, []int64) error
+	DeletePostsBefore(context.Context, time.Time) (int64, error)
+}
+
+type postRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostRepository(database *pgxpool.Pool) PostRepository {
+	return &postRepository{
+		db: database,
+	}
+}
+
+func (r *postRepository) SavePosts(ctx context.Context, posts []*entity.Post) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	batch := &pgx.Batch{}
+	for _, post := range posts {
+
+		var pollQuestion *string
+		var pollOptions []string
+		if post.Poll != nil {
+			pollQuestion, pollOptions = &post.Poll.Question, post.Poll.Options
+		}
+
+		batch.Queue(
+			`
+			INSERT INTO post(channel_id, post_id, grouped_id, topic_id, date, author, from_bot, text, caption, urls,
+				forwarded_from, poll_question, poll_options, views, forwards, reactions)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, '{}'::TEXT[]), $11, $12, $13, $14, $15, $16)
+			ON CONFLICT (channel_id, post_id)
+			DO UPDATE SET text = EXCLUDED.text, caption = EXCLUDED.caption, urls = EXCLUDED.urls,
+				poll_question = EXCLUDED.poll_question, poll_options = EXCLUDED.poll_options,
+				views = EXCLUDED.views, forwards = EXCLUDED.forwards, reactions = EXCLUDED.reactions
+			`,
+			post.ChannelID, post.ID, post.GroupedID, post.TopicID, post.Date, post.Author, post.FromBot, post.Text, post.Caption, post.URLs,
+			post.ForwardedFrom, pollQuestion, pollOptions, post.Views, post.Forwards, post.Reactions)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	return nil
+}
+
+// GetPosts returns posts newer than afterID and not older than since,
+// from newest to oldest.
+func (r *postRepository) GetPosts(ctx context.Context, channelID int64, afterID int64, since time.Time, limit int) ([]*entity.Post, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT channel_id, post_id, grouped_id, topic_id, date, author, from_bot, text, caption, urls,
+			forwarded_from, poll_question, poll_options, views, forwards, reactions
+		FROM post
+		WHERE channel_id = $1 AND post_id > $2 AND date >= $3
+		ORDER BY post_id DESC
+		LIMIT $4
+		`,
+		channelID, afterID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+
+		var post entity.Post
+		var pollQuestion *string
+		var pollOptions []string
+		if err := rows.Scan(&post.ChannelID, &post.ID, &post.GroupedID, &post.TopicID, &post.Date, &post.Author, &post.FromBot, &post.Text, &post.Caption, &post.URLs,
+			&post.ForwardedFrom, &pollQuestion, &pollOptions, &post.Views, &post.Forwards, &post.Reactions); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+		}
+
+		if pollQuestion != nil {
+			post.Poll = &entity.Poll{Question: *pollQuestion, Options: pollOptions}
+		}
+
+		posts = append(posts, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingStreamFailed, err)
+	}
+
+	return posts, nil
+}
+
+func (r *postRepository) LatestPostID(ctx context.Context, channelID int64) (int64, error) {
+
+	if err := ctx.Err(); err != nil {
+		return 0, ErrTimeLimit
+	}
+
+	var latest int64
+	err := r.db.QueryRow(ctx,
+		`
+		SELECT COALESCE(MAX(post_id), 0)
+		FROM post
+		WHERE channel_id = $1
+		`,
+		channelID).Scan(&latest)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return latest, nil
+}
+
+func (r *postRepository) DeletePosts(ctx context.Context, channelID int64, postIDs []int64) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	_, err := r.db.Exec(ctx,
+		`
+		DELETE FROM post
+		WHERE channel_id = $1 AND post_id = ANY($2)
+		`,
+		channelID, postIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDeletingFailed, err)
+	}
+
+	return nil
+}
+
+func (r *postRepository) DeletePostsBefore(ctx context.Context, before time.Time) (int64, error) {
+
+	if err := ctx.Err(); err != nil {
+		return 0, ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		DELETE FROM post
+		WHERE date < $1
+		`,
+		before)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrDeletingFailed, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
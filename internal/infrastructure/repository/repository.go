@@ -7,6 +7,7 @@ import (
 	"log"
 	"post-analyzer/internal/domain/entity"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,15 +23,24 @@ var (
 	ErrUpdateFailed    = errors.New("db update failed")
 	ErrDeletingFailed  = errors.New("db deleting failed")
 
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
 	ErrMappingFailed       = errors.New("mapping to subscription struct failed")
 	ErrReadingStreamFailed = errors.New("error during stream reading")
 )
 
 type SubscriptionRepository interface {
 	AddSubscription(context.Context, *entity.Subscription) error
+	GetSubscription(context.Context, int64) (*entity.Subscription, error)
 	GetSubscriptions(context.Context, int64) ([]*entity.Subscription, error)
+	GetAllSubscriptions(context.Context) ([]*entity.Subscription, error)
 	UpdateSubscription(context.Context, *entity.Subscription) error
+	UpdateLastChecked(context.Context, *entity.Subscription) error
+	UpdateReadOptions(context.Context, *entity.Subscription) error
+	UpdateAnalysisOptions(context.Context, *entity.Subscription) error
+	RescheduleSubscription(context.Context, *entity.Subscription, entity.Schedule) error
 	DeleteSubscription(context.Context, *entity.Subscription) error
+	ChannelFollowed(context.Context, int64) (bool, error)
 }
 
 type subscriptionRepository struct {
@@ -63,24 +73,20 @@ func (r *subscriptionRepository) AddSubscription(ctx context.Context, sub *entit
 		}
 	}()
 
-	_, err = tx.Exec(ctx,
-		`
-		INSERT INTO channel(channel_id, username)
-		VALUES ($1, $2)
-		ON CONFLICT (channel_id)
-		DO UPDATE SET username = EXCLUDED.username
-		`,
-		sub.ChannelID, sub.ChannelUsername)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	if err = saveChannelName(ctx, tx, sub.ChannelID, sub.ChannelUsername, sub.ChannelTitle); err != nil {
+		return err
 	}
 
-	_, err = tx.Exec(ctx,
+	err = tx.QueryRow(ctx,
 		`
-		INSERT INTO subscription(chat_id, channel_id, last_checked_id, send_time, schedule_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO subscription(chat_id, channel_id, last_checked_id, schedule, schedule_id, active,
+			topic_id, exclude_bots, include_comments, model, temperature, top_p, system_prompt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
 		`,
-		sub.ChatID, sub.ChannelID, sub.LastCheckedPostID, sub.SendingTime, sub.ScheduleID)
+		sub.ChatID, sub.ChannelID, sub.LastCheckedPostID, sub.Schedule.String(), sub.ScheduleID, sub.Active,
+		sub.TopicID, sub.ExcludeBots, sub.IncludeComments,
+		sub.Analysis.Model, sub.Analysis.Temperature, sub.Analysis.TopP, sub.Analysis.Prompt).Scan(&sub.ID)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrInsertionFailed, err)
 	}
@@ -93,6 +99,40 @@ func (r *subscriptionRepository) AddSubscription(ctx context.Context, sub *entit
 	return nil
 }
 
+// GetSubscription returns the subscription with the given ID, so scheduled
+// jobs read the options and position stored at the time they run.
+func (r *subscriptionRepository) GetSubscription(ctx context.Context, id int64) (*entity.Subscription, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT s.id, s.chat_id, s.channel_id, COALESCE(c.username, ''), c.title, s.last_checked_id, s.schedule,
+			COALESCE(cs.timezone, ''), s.schedule_id, s.active, s.topic_id, s.exclude_bots, s.include_comments,
+			s.model, s.temperature, s.top_p, s.system_prompt
+		FROM subscription s INNER JOIN channel c USING(channel_id)
+			LEFT JOIN chat_settings cs ON cs.chat_id = s.chat_id
+		WHERE s.id = $1
+		`,
+		id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	subs, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(subs) == 0 {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	return subs[0], nil
+}
+
 func (r *subscriptionRepository) GetSubscriptions(ctx context.Context, chatID int64) ([]*entity.Subscription, error) {
 
 	if err := ctx.Err(); err != nil {
@@ -101,32 +141,79 @@ func (r *subscriptionRepository) GetSubscriptions(ctx context.Context, chatID in
 
 	rows, err := r.db.Query(ctx,
 		`
-		SELECT s.chat_id, s.channel_id, c.username, s.last_checked_id, s.send_time, s.schedule_id
+		SELECT s.id, s.chat_id, s.channel_id, COALESCE(c.username, ''), c.title, s.last_checked_id, s.schedule,
+			COALESCE(cs.timezone, ''), s.schedule_id, s.active, s.topic_id, s.exclude_bots, s.include_comments,
+			s.model, s.temperature, s.top_p, s.system_prompt
 		FROM subscription s INNER JOIN channel c USING(channel_id)
+			LEFT JOIN chat_settings cs ON cs.chat_id = s.chat_id
 		WHERE s.chat_id = $1
+		ORDER BY c.username, s.schedule
 		`,
 		chatID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
 	}
+
+	return scanSubscriptions(rows)
+}
+
+func (r *subscriptionRepository) GetAllSubscriptions(ctx context.Context) ([]*entity.Subscription, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT s.id, s.chat_id, s.channel_id, COALESCE(c.username, ''), c.title, s.last_checked_id, s.schedule,
+			COALESCE(cs.timezone, ''), s.schedule_id, s.active, s.topic_id, s.exclude_bots, s.include_comments,
+			s.model, s.temperature, s.top_p, s.system_prompt
+		FROM subscription s INNER JOIN channel c USING(channel_id)
+			LEFT JOIN chat_settings cs ON cs.chat_id = s.chat_id
+		`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return scanSubscriptions(rows)
+}
+
+func scanSubscriptions(rows pgx.Rows) ([]*entity.Subscription, error) {
+
 	defer rows.Close()
 
 	var subs []*entity.Subscription
 	for rows.Next() {
 
 		var sub entity.Subscription
+		var schedule string
 		err := rows.Scan(
+			&sub.ID,
 			&sub.ChatID,
 			&sub.ChannelID,
 			&sub.ChannelUsername,
+			&sub.ChannelTitle,
 			&sub.LastCheckedPostID,
-			&sub.SendingTime,
+			&schedule,
+			&sub.Timezone,
 			&sub.ScheduleID,
+			&sub.Active,
+			&sub.TopicID,
+			&sub.ExcludeBots,
+			&sub.IncludeComments,
+			&sub.Analysis.Model,
+			&sub.Analysis.Temperature,
+			&sub.Analysis.TopP,
+			&sub.Analysis.Prompt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
 		}
 
+		if sub.Schedule, err = entity.ParseSchedule(schedule); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+		}
+
 		subs = append(subs, &sub)
 	}
 
@@ -146,14 +233,113 @@ func (r *subscriptionRepository) UpdateSubscription(ctx context.Context, sub *en
 	_, err := r.db.Exec(ctx,
 		`
 		UPDATE subscription
-		SET last_checked_id = $1, schedule_id = $2
-		WHERE chat_id = $3 AND channel_id = $4 AND send_time = $5
+		SET last_checked_id = $1, schedule_id = $2, active = $3
+		WHERE chat_id = $4 AND channel_id = $5 AND schedule = $6
+		`,
+		sub.LastCheckedPostID, sub.ScheduleID, sub.Active, sub.ChatID, sub.ChannelID, sub.Schedule.String())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// UpdateLastChecked moves only the read position, so a digest job started
+// before a /pause or /edit does not bring back the state it captured.
+func (r *subscriptionRepository) UpdateLastChecked(ctx context.Context, sub *entity.Subscription) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	_, err := r.db.Exec(ctx,
+		`
+		UPDATE subscription
+		SET last_checked_id = $1
+		WHERE id = $2
+		`,
+		sub.LastCheckedPostID, sub.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// UpdateReadOptions stores the options apart from the rest of the row, so
+// a running digest job does not write back the options it started with.
+func (r *subscriptionRepository) UpdateReadOptions(ctx context.Context, sub *entity.Subscription) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		UPDATE subscription
+		SET topic_id = $1, exclude_bots = $2, include_comments = $3
+		WHERE chat_id = $4 AND channel_id = $5 AND schedule = $6
 		`,
-		sub.LastCheckedPostID, sub.ScheduleID, sub.ChatID, sub.ChannelID, sub.SendingTime)
+		sub.TopicID, sub.ExcludeBots, sub.IncludeComments, sub.ChatID, sub.ChannelID, sub.Schedule.String())
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// UpdateAnalysisOptions stores the model settings apart from the rest of
+// the row, like UpdateReadOptions.
+func (r *subscriptionRepository) UpdateAnalysisOptions(ctx context.Context, sub *entity.Subscription) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		UPDATE subscription
+		SET model = $1, temperature = $2, top_p = $3, system_prompt = $4
+		WHERE chat_id = $5 AND channel_id = $6 AND schedule = $7
+		`,
+		sub.Analysis.Model, sub.Analysis.Temperature, sub.Analysis.TopP, sub.Analysis.Prompt,
+		sub.ChatID, sub.ChannelID, sub.Schedule.String())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUpdateFailed, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) RescheduleSubscription(ctx context.Context, sub *entity.Subscription, previous entity.Schedule) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		UPDATE subscription
+		SET schedule = $1, schedule_id = $2
+		WHERE chat_id = $3 AND channel_id = $4 AND schedule = $5
+		`,
+		sub.Schedule.String(), sub.ScheduleID, sub.ChatID, sub.ChannelID, previous.String())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateFailed, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+
 	return nil
 }
 
@@ -163,15 +349,41 @@ func (r *subscriptionRepository) DeleteSubscription(ctx context.Context, sub *en
 		return err
 	}
 
-	_, err := r.db.Exec(ctx,
+	tag, err := r.db.Exec(ctx,
 		`
 		DELETE FROM subscription
-		WHERE chat_id = $1 AND channel_id = $2 AND send_time = $3
+		WHERE chat_id = $1 AND channel_id = $2 AND schedule = $3
 		`,
-		sub.ChatID, sub.ChannelID, sub.SendingTime)
+		sub.ChatID, sub.ChannelID, sub.Schedule.String())
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrDeletingFailed, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+
 	return nil
 }
+
+// ChannelFollowed reports whether any subscription, paused ones included,
+// or any bundle still reads the channel.
+func (r *subscriptionRepository) ChannelFollowed(ctx context.Context, channelID int64) (bool, error) {
+
+	if err := ctx.Err(); err != nil {
+		return false, ErrTimeLimit
+	}
+
+	var followed bool
+	err := r.db.QueryRow(ctx,
+		`
+		SELECT EXISTS(SELECT 1 FROM subscription WHERE channel_id = $1)
+			OR EXISTS(SELECT 1 FROM bundle_channel WHERE channel_id = $1)
+		`,
+		channelID).Scan(&followed)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return followed, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"post-analyzer/internal/domain/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SentPostRepository interface {
+	SaveSentPosts(context.Context, []*entity.SentPost) error
+	GetSentPosts(context.Context, int64, int64, time.Time) ([]*entity.SentPost, error)
+	DeleteSentPosts(context.Context, int64, int64, []int64) error
+	DeleteSentPostsBefore(context.Context, time.Time) (int64, error)
+}
+
+type sentPostRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSentPostRepository(database *pgxpool.Pool) SentPostRepository {
+	return &sentPostRepository{
+		db: database,
+	}
+}
+
+func (r *sentPostRepository) SaveSentPosts(ctx context.Context, posts []*entity.SentPost) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	batch := &pgx.Batch{}
+	for _, post := range posts {
+		batch.Queue(
+			`
+			INSERT INTO sent_post(chat_id, channel_id, post_id, link, excerpt, sent_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (chat_id, channel_id, post_id)
+			DO UPDATE SET excerpt = EXCLUDED.excerpt, sent_at = EXCLUDED.sent_at
+			`,
+			post.ChatID, post.ChannelID, post.PostID, post.Link, post.Excerpt, post.SentAt)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	return nil
+}
+
+// GetSentPosts returns posts of the channel sent to the chat not earlier than since.
+func (r *sentPostRepository) GetSentPosts(ctx context.Context, chatID int64, channelID int64, since time.Time) ([]*entity.SentPost, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	rows, err := r.db.Query(ctx,
+		`
+		SELECT chat_id, channel_id, post_id, link, excerpt, sent_at
+		FROM sent_post
+		WHERE chat_id = $1 AND channel_id = $2 AND sent_at >= $3
+		ORDER BY post_id
+		`,
+		chatID, channelID, since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.SentPost
+	for rows.Next() {
+
+		var post entity.SentPost
+		if err := rows.Scan(&post.ChatID, &post.ChannelID, &post.PostID, &post.Link, &post.Excerpt, &post.SentAt); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMappingFailed, err)
+		}
+
+		posts = append(posts, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingStreamFailed, err)
+	}
+
+	return posts, nil
+}
+
+func (r *sentPostRepository) DeleteSentPosts(ctx context.Context, chatID int64, channelID int64, postIDs []int64) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	_, err := r.db.Exec(ctx,
+		`
+		DELETE FROM sent_post
+		WHERE chat_id = $1 AND channel_id = $2 AND post_id = ANY($3)
+		`,
+		chatID, channelID, postIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDeletingFailed, err)
+	}
+
+	return nil
+}
+
+func (r *sentPostRepository) DeleteSentPostsBefore(ctx context.Context, before time.Time) (int64, error) {
+
+	if err := ctx.Err(); err != nil {
+		return 0, ErrTimeLimit
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`
+		DELETE FROM sent_post
+		WHERE sent_at < $1
+		`,
+		before)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrDeletingFailed, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
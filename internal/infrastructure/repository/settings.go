@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"post-analyzer/internal/domain/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SettingsRepository interface {
+	GetSettings(context.Context, int64) (*entity.ChatSettings, error)
+	SaveSettings(context.Context, *entity.ChatSettings) error
+}
+
+type settingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSettingsRepository(database *pgxpool.Pool) SettingsRepository {
+	return &settingsRepository{
+		db: database,
+	}
+}
+
+func (r *settingsRepository) GetSettings(ctx context.Context, chatID int64) (*entity.ChatSettings, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeLimit
+	}
+
+	settings := &entity.ChatSettings{
+		ChatID: chatID,
+	}
+
+	err := r.db.QueryRow(ctx,
+		`
+		SELECT timezone, language, model, temperature, top_p, system_prompt
+		FROM chat_settings
+		WHERE chat_id = $1
+		`,
+		chatID).Scan(&settings.Timezone, &settings.Language, &settings.Analysis.Model, &settings.Analysis.Temperature,
+		&settings.Analysis.TopP, &settings.Analysis.Prompt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSelectionFailed, err)
+	}
+
+	return settings, nil
+}
+
+func (r *settingsRepository) SaveSettings(ctx context.Context, settings *entity.ChatSettings) error {
+
+	if err := ctx.Err(); err != nil {
+		return ErrTimeLimit
+	}
+
+	_, err := r.db.Exec(ctx,
+		`
+		INSERT INTO chat_settings(chat_id, timezone, language, model, temperature, top_p, system_prompt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (chat_id)
+		DO UPDATE SET timezone = EXCLUDED.timezone, language = EXCLUDED.language, model = EXCLUDED.model, temperature = EXCLUDED.temperature,
+			top_p = EXCLUDED.top_p, system_prompt = EXCLUDED.system_prompt
+		`,
+		settings.ChatID, settings.Timezone, settings.Language, settings.Analysis.Model, settings.Analysis.Temperature,
+		settings.Analysis.TopP, settings.Analysis.Prompt)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInsertionFailed, err)
+	}
+
+	return nil
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"post-analyzer/internal/domain/entity"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
@@ -14,36 +15,131 @@ var (
 )
 
 type Scheduler interface {
-	ScheduleEvent(sub *entity.Subscription, job func()) (int, error)
+	ScheduleEvent(schedule entity.Schedule, timezone string, job func()) (int, error)
+	RemoveEvent(scheduleID int)
+	NextRun(scheduleID int) time.Time
+	Delay(scheduleID int, wait time.Duration, job func())
 }
 
 type scheduler struct {
 	cron *cron.Cron
+
+	mu      sync.Mutex
+	delayed map[int]*time.Timer
 }
 
 func NewScheduler() *scheduler {
 	return &scheduler{
-		cron: cron.New(),
+		cron:    cron.New(),
+		delayed: make(map[int]*time.Timer),
 	}
 }
 
-func (s scheduler) Start() {
+func (s *scheduler) Start() {
 	s.cron.Start()
 }
 
-func (s scheduler) Stop() {
+func (s *scheduler) Stop() {
 	s.cron.Stop()
 }
 
-func (s scheduler) ScheduleEvent(sub *entity.Subscription, job func()) (int, error) {
+func (s *scheduler) ScheduleEvent(schedule entity.Schedule, timezone string, job func()) (int, error) {
+
+	specs := schedule.CronSpecs()
+	if len(specs) == 0 {
+		return 0, fmt.Errorf("%w: empty schedule", ErrSchedulingEvent)
+	}
+
+	var combined multiSchedule
+	for _, spec := range specs {
 
-	parts := strings.Split(sub.SendingTime, ":")
-	cronSpec := fmt.Sprintf("%s %s * * *", parts[1], parts[0])
+		if timezone != "" {
+			spec = fmt.Sprintf("CRON_TZ=%s %s", timezone, spec)
+		}
 
-	schedID, err := s.cron.AddFunc(cronSpec, job)
-	if err != nil {
-		return 0, fmt.Errorf("%w: %s", ErrSchedulingEvent, err)
+		parsed, err := cron.ParseStandard(spec)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrSchedulingEvent, err)
+		}
+
+		combined = append(combined, parsed)
 	}
 
+	schedID := s.cron.Schedule(combined, cron.FuncJob(job))
+
 	return int(schedID), nil
 }
+
+// RemoveEvent also cancels a delayed run of the event, so nothing is sent
+// for it once it is removed.
+func (s *scheduler) RemoveEvent(scheduleID int) {
+
+	s.cron.Remove(cron.EntryID(scheduleID))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.delayed[scheduleID]; ok {
+		timer.Stop()
+		delete(s.delayed, scheduleID)
+	}
+}
+
+func (s *scheduler) NextRun(scheduleID int) time.Time {
+	return s.cron.Entry(cron.EntryID(scheduleID)).Next
+}
+
+// Delay runs the job of a scheduled event once more after wait. An event
+// keeps at most one delayed run, the latest one replaces the pending one,
+// and no run is delayed past the next scheduled run, which does the work
+// anyway.
+func (s *scheduler) Delay(scheduleID int, wait time.Duration, job func()) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.delayed[scheduleID]; ok {
+		timer.Stop()
+		delete(s.delayed, scheduleID)
+	}
+
+	entry := s.cron.Entry(cron.EntryID(scheduleID))
+	if !entry.Valid() {
+		return
+	}
+	if !entry.Next.IsZero() && !time.Now().Add(wait).Before(entry.Next) {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(wait, func() {
+
+		s.mu.Lock()
+		current := s.delayed[scheduleID] == timer
+		if current {
+			delete(s.delayed, scheduleID)
+		}
+		s.mu.Unlock()
+
+		if current {
+			job()
+		}
+	})
+	s.delayed[scheduleID] = timer
+}
+
+// multiSchedule fires at the earliest activation of any of its parts,
+// so a single cron entry can cover several delivery times.
+type multiSchedule []cron.Schedule
+
+func (m multiSchedule) Next(t time.Time) time.Time {
+
+	var next time.Time
+	for _, schedule := range m {
+		if candidate := schedule.Next(t); !candidate.IsZero() && (next.IsZero() || candidate.Before(next)) {
+			next = candidate
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/domain/validation"
+	"post-analyzer/internal/infrastructure/repository"
+)
+
+// SetModelOptions changes the model settings of the chat's subscriptions to
+// the channel, or the chat defaults when no channel is given, and returns
+// the resulting settings.
+func (uc useCaseManager) SetModelOptions(ctx context.Context, mr *dto.ModelRequest) (string, error) {
+
+	target := &entity.Subscription{
+		ChatID: mr.ChatID,
+	}
+	var options []string
+
+	validationChain := validation.ModelOptionsValidator(
+		validation.ChannelNameValidator(
+			validation.InviteValidator(nil, uc.tgc),
+		),
+		&options,
+		uc.models,
+	)
+
+	if err := validationChain(ctx, mr.Message, target); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	return uc.updateAnalysis(ctx, target, func(analysis *entity.AnalysisOptions) error {
+
+		for _, option := range options {
+			if err := analysis.Set(option); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetPrompt replaces the system prompt the same way SetModelOptions
+// changes the model settings.
+func (uc useCaseManager) SetPrompt(ctx context.Context, pr *dto.PromptRequest) (string, error) {
+
+	target := &entity.Subscription{
+		ChatID: pr.ChatID,
+	}
+	var prompt string
+
+	validationChain := validation.PromptValidator(
+		validation.ChannelNameValidator(
+			validation.InviteValidator(nil, uc.tgc),
+		),
+		&prompt,
+	)
+
+	if err := validationChain(ctx, pr.Message, target); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	return uc.updateAnalysis(ctx, target, func(analysis *entity.AnalysisOptions) error {
+		return analysis.SetPrompt(prompt)
+	})
+}
+
+func (uc useCaseManager) updateAnalysis(ctx context.Context, target *entity.Subscription, apply func(*entity.AnalysisOptions) error) (string, error) {
+
+	// chat defaults are read by every digest when it runs
+	if target.ChannelID == 0 && target.ChannelUsername == "" {
+
+		settings, err := uc.settings.GetSettings(ctx, target.ChatID)
+		if err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		if err := apply(&settings.Analysis); err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		if err := uc.settings.SaveSettings(ctx, settings); err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		return settings.Analysis.String(), nil
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, target.ChatID)
+	if err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	var updated *entity.Subscription
+	for _, sub := range subs {
+
+		if !sameChannel(sub, target) {
+			continue
+		}
+
+		if err := apply(&sub.Analysis); err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		if err := uc.repo.UpdateAnalysisOptions(ctx, sub); err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		updated = sub
+	}
+
+	if updated == nil {
+		return "", presenter.PresentError(repository.ErrSubscriptionNotFound)
+	}
+
+	return updated.Analysis.String(), nil
+}
@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/domain/validation"
+	"post-analyzer/internal/infrastructure/repository"
+)
+
+func (uc useCaseManager) CreateBundle(ctx context.Context, br *dto.BundleRequest) error {
+
+	bundle := &entity.Bundle{
+		ChatID: br.ChatID,
+	}
+
+	validationChain := validation.BundleArgsValidator(
+		validation.BundleNameValidator(
+			validation.BundleScheduleValidator(
+				validation.BundleChannelsValidator(nil, uc.tgc, uc.feeds),
+			),
+		),
+	)
+
+	if err := validationChain(ctx, br.Message, bundle); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	settings, err := uc.settings.GetSettings(ctx, br.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+	bundle.Timezone = settings.Timezone
+
+	if err := uc.bundles.AddBundle(ctx, bundle); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	if bundle.ScheduleID, err = uc.sched.ScheduleEvent(bundle.Schedule, bundle.Timezone, uc.bundleJob(bundle)); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	if err := uc.bundles.UpdateBundle(ctx, bundle); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	for _, channel := range bundle.Channels {
+		uc.src.Watch(channel.ChannelID)
+	}
+
+	return nil
+}
+
+func (uc useCaseManager) DeleteBundle(ctx context.Context, br *dto.BundleRequest) error {
+
+	target := &entity.Bundle{
+		ChatID: br.ChatID,
+	}
+
+	validationChain := validation.BundleNameValidator(nil)
+
+	if err := validationChain(ctx, br.Message, target); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	bundle, err := uc.findBundle(ctx, br.ChatID, target.Name)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	if err := uc.bundles.DeleteBundle(ctx, bundle); err != nil {
+		return presenter.PresentError(err)
+	}
+	uc.sched.RemoveEvent(bundle.ScheduleID)
+
+	channelIDs := make([]int64, 0, len(bundle.Channels))
+	for _, channel := range bundle.Channels {
+		channelIDs = append(channelIDs, channel.ChannelID)
+	}
+	uc.unwatchUnfollowed(ctx, channelIDs)
+
+	return nil
+}
+
+func (uc useCaseManager) ListBundles(ctx context.Context, br *dto.BundleRequest) ([]dto.BundleInfo, error) {
+
+	bundles, err := uc.bundles.GetBundles(ctx, br.ChatID)
+	if err != nil {
+		return nil, presenter.PresentError(err)
+	}
+
+	location, err := uc.chatLocation(ctx, br.ChatID)
+	if err != nil {
+		return nil, presenter.PresentError(err)
+	}
+
+	infos := make([]dto.BundleInfo, 0, len(bundles))
+	for _, bundle := range bundles {
+
+		channels := make([]string, 0, len(bundle.Channels))
+		for _, channel := range bundle.Channels {
+			channels = append(channels, entity.ChannelName(channel.ChannelUsername, channel.ChannelTitle))
+		}
+
+		infos = append(infos, dto.BundleInfo{
+			Name:     bundle.Name,
+			Channels: channels,
+			Schedule: bundle.Schedule.String(),
+			NextRun:  uc.sched.NextRun(bundle.ScheduleID).In(location),
+		})
+	}
+
+	return infos, nil
+}
+
+func (uc useCaseManager) findBundle(ctx context.Context, chatID int64, name string) (*entity.Bundle, error) {
+
+	bundles, err := uc.bundles.GetBundles(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bundle := range bundles {
+		if bundle.Name == name {
+			return bundle, nil
+		}
+	}
+
+	return nil, repository.ErrBundleNotFound
+}
+
+func (uc useCaseManager) rescheduleBundle(ctx context.Context, bundle *entity.Bundle) error {
+
+	previousID := bundle.ScheduleID
+
+	var err error
+	if bundle.ScheduleID, err = uc.sched.ScheduleEvent(bundle.Schedule, bundle.Timezone, uc.bundleJob(bundle)); err != nil {
+		return err
+	}
+
+	if err := uc.bundles.UpdateBundle(ctx, bundle); err != nil {
+		uc.sched.RemoveEvent(bundle.ScheduleID)
+		return err
+	}
+	uc.sched.RemoveEvent(previousID)
+
+	return nil
+}
+
+func (uc useCaseManager) bundleJob(bundle *entity.Bundle) func() {
+
+	var job func()
+	job = func() {
+
+		analysisCtx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+		defer cancel()
+
+		var ranked []rankedPost
+		truncated, limited, uncounted := 0, false, false
+
+		// positions are committed only when the whole bundle has been read,
+		// so a delayed rerun does not skip channels fetched before a flood wait
+		latest := make(map[*entity.BundleChannel]int64)
+
+		settings := uc.chatSettings(analysisCtx, bundle.ChatID)
+		language := settings.OutputLanguage()
+
+		var notes []string
+		for _, channel := range bundle.Channels {
+
+			label := entity.ChannelLabel(channel.ChannelUsername, channel.ChannelTitle)
+			channelNotes, err := uc.corrections(analysisCtx, bundle.ChatID, channel.ChannelID, label, language)
+			if err != nil {
+				log.Println(err)
+			}
+			notes = append(notes, channelNotes...)
+		}
+		uc.sendCorrections(analysisCtx, bundle.ChatID, language, notes)
+
+		subscribed := uc.subscribedChannels(analysisCtx, bundle.ChatID)
+
+		for _, channel := range bundle.Channels {
+
+			history, err := uc.src.ChannelPosts(analysisCtx, channel.ChannelID, channel.LastCheckedPostID)
+			if err != nil {
+				if uc.delayOnFloodWait(err, bundle.ScheduleID, job) {
+					return
+				}
+				log.Println(err)
+				continue
+			}
+
+			if len(history.Posts) == 0 {
+				continue
+			}
+			latest[channel] = history.NextReadID()
+			truncated += history.Truncated
+			limited = limited || history.Limited
+			uncounted = uncounted || history.Limited && history.Truncated == 0
+
+			// filtered out messages still count as read
+			if sub, ok := subscribed[channel.ChannelID]; ok {
+				if history.Posts = sub.Filter(history.Posts); len(history.Posts) == 0 {
+					continue
+				}
+			}
+
+			// every channel is scored against its own baseline
+			label := entity.ChannelLabel(channel.ChannelUsername, channel.ChannelTitle)
+			ranked = append(ranked, rankPosts(history.Posts, channel.ChannelUsername, label)...)
+		}
+
+		// posts removed by the filters are read too, so the positions
+		// move on even when nothing is left to send
+		for channel, id := range latest {
+			channel.LastCheckedPostID = id
+		}
+
+		if err := uc.bundles.UpdateBundle(analysisCtx, bundle); err != nil {
+			log.Println(err)
+			return
+		}
+
+		if len(ranked) == 0 {
+			return
+		}
+		sortRanked(ranked)
+
+		// identical posts (reposts, cross-posting) are sent once with all their sources
+		var order []string
+		sources := make(map[string][]string)
+		first := make(map[string]*entity.Post)
+		var analyzed []rankedPost
+
+		for _, r := range ranked {
+
+			text := strings.TrimSpace(r.post.Content())
+			if _, ok := sources[text]; !ok {
+				if len(order) == maxAnalyzedPosts {
+					continue
+				}
+				order = append(order, text)
+				first[text] = r.post
+			}
+			sources[text] = append(sources[text], r.label)
+			analyzed = append(analyzed, r)
+		}
+
+		// comments are loaded once the posts are picked, repeated posts
+		// carry the comments of their first source
+		for channelID, sub := range subscribed {
+
+			var posts []*entity.Post
+			for _, text := range order {
+				if first[text].ChannelID == channelID {
+					posts = append(posts, first[text])
+				}
+			}
+			uc.loadComments(analysisCtx, sub, posts)
+		}
+
+		var postsBuilder strings.Builder
+		for _, text := range order {
+			fmt.Fprintf(&postsBuilder, "[%s] %s\n", strings.Join(sources[text], ", "), strings.TrimSpace(first[text].Content()))
+		}
+
+		header := presenter.Text(language, presenter.MsgBundleHeader, bundle.Name)
+		if limited {
+			// one channel without a count leaves the total unknown
+			if uncounted {
+				truncated = 0
+			}
+			header += truncatedHeader(language, len(order), truncated)
+		}
+		if len(analyzed) < len(ranked) {
+			header += presenter.Text(language, presenter.MsgRankedHeader, len(ranked), len(analyzed))
+		}
+		// one analysis covers all channels, so the chat's model settings apply
+		// rather than those of any single subscription
+		footer := engagementFooter(ranked, language)
+		if err := uc.analyzeAndNotify(analysisCtx, settings, entity.AnalysisOptions{}, header, postsBuilder.String(), footer); err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, channel := range bundle.Channels {
+
+			var sent []*entity.Post
+			for _, r := range analyzed {
+				if r.post.ChannelID == channel.ChannelID {
+					sent = append(sent, r.post)
+				}
+			}
+
+			if len(sent) > 0 {
+				uc.rememberSent(analysisCtx, bundle.ChatID, channel.ChannelID, channel.ChannelUsername, sent)
+			}
+		}
+	}
+
+	return job
+}
+
+// subscribedChannels maps channels to the chat's subscriptions to them.
+// Reading options are set on subscriptions and apply to the same channels
+// in bundles, a failed read leaves bundles unfiltered.
+func (uc useCaseManager) subscribedChannels(ctx context.Context, chatID int64) map[int64]*entity.Subscription {
+
+	subs, err := uc.repo.GetSubscriptions(ctx, chatID)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
+	subscribed := make(map[int64]*entity.Subscription, len(subs))
+	for _, sub := range subs {
+		subscribed[sub.ChannelID] = sub
+	}
+
+	return subscribed
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+)
+
+const (
+	correctionWindow = 72 * time.Hour
+	excerptLength    = 80
+)
+
+// rememberSent records the posts of a delivered digest, so their later
+// edits and deletions can be reported to the chat.
+func (uc useCaseManager) rememberSent(ctx context.Context, chatID, channelID int64, username string, posts []*entity.Post) {
+
+	now := time.Now()
+
+	sent := make([]*entity.SentPost, 0, len(posts))
+	for _, post := range posts {
+		sent = append(sent, &entity.SentPost{
+			ChatID:    chatID,
+			ChannelID: channelID,
+			PostID:    post.ID,
+			Link:      post.URL(username),
+			Excerpt:   post.Excerpt(excerptLength),
+			SentAt:    now,
+		})
+	}
+
+	if err := uc.sent.SaveSentPosts(ctx, sent); err != nil {
+		log.Printf("failed to remember digest posts for chat %d: %v", chatID, err)
+	}
+
+	if _, err := uc.sent.DeleteSentPostsBefore(ctx, now.Add(-correctionWindow)); err != nil {
+		log.Printf("failed to clean up digest posts: %v", err)
+	}
+}
+
+// corrections describes posts of the channel sent to the chat earlier and
+// edited or deleted since then. Reported posts are not reported again
+// until they change once more.
+func (uc useCaseManager) corrections(ctx context.Context, chatID, channelID int64, label string, language entity.Language) ([]string, error) {
+
+	sent, err := uc.sent.GetSentPosts(ctx, chatID, channelID, time.Now().Add(-correctionWindow))
+	if err != nil || len(sent) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(sent))
+	for _, post := range sent {
+		ids = append(ids, post.PostID)
+	}
+
+	current, err := uc.src.Posts(ctx, channelID, ids)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []string
+	var deleted []int64
+	var edited []*entity.SentPost
+
+	for _, post := range sent {
+
+		updated, ok := current[post.PostID]
+		switch {
+
+		case !ok:
+			notes = append(notes, presenter.Text(language, presenter.MsgPostDeleted, label, post.Excerpt))
+			deleted = append(deleted, post.PostID)
+
+		case updated.EditDate.After(post.SentAt):
+			notes = append(notes, presenter.Text(language, presenter.MsgPostEdited, label, post.Excerpt, post.Link, updated.Excerpt(excerptLength)))
+			post.Excerpt = updated.Excerpt(excerptLength)
+			post.SentAt = time.Now()
+			edited = append(edited, post)
+		}
+	}
+
+	if len(deleted) > 0 {
+		if err := uc.sent.DeleteSentPosts(ctx, chatID, channelID, deleted); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(edited) > 0 {
+		if err := uc.sent.SaveSentPosts(ctx, edited); err != nil {
+			return nil, err
+		}
+	}
+
+	return notes, nil
+}
+
+func (uc useCaseManager) sendCorrections(ctx context.Context, chatID int64, language entity.Language, notes []string) {
+
+	if len(notes) == 0 {
+		return
+	}
+
+	if err := uc.notifier.NotifyWithText(ctx, chatID, presenter.Text(language, presenter.MsgCorrectionsHeader)+strings.Join(notes, "\n\n")); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/domain/validation"
+)
+
+// ChatLanguage returns the language the chat is answered in. A chat without
+// one gets the language of the Telegram client when it is supported, so
+// the choice sticks for digests sent later by the scheduler.
+func (uc useCaseManager) ChatLanguage(ctx context.Context, lr *dto.LanguageRequest) entity.Language {
+
+	settings, err := uc.settings.GetSettings(ctx, lr.ChatID)
+	if err != nil {
+		log.Println(err)
+		return entity.DefaultLanguage
+	}
+
+	if settings.Language != "" {
+		return settings.Language
+	}
+
+	language, ok := entity.ParseLanguage(lr.ClientLanguage)
+	if !ok {
+		return entity.DefaultLanguage
+	}
+
+	settings.Language = language
+	if err := uc.settings.SaveSettings(ctx, settings); err != nil {
+		log.Println(err)
+	}
+
+	return language
+}
+
+// SetLanguage changes the language of bot replies and digests of the chat
+// and returns it.
+func (uc useCaseManager) SetLanguage(ctx context.Context, lr *dto.LanguageRequest) (entity.Language, error) {
+
+	target := &entity.Subscription{
+		ChatID: lr.ChatID,
+	}
+	var language entity.Language
+
+	validationChain := validation.SingleArgValidator(
+		validation.LanguageValidator(nil, &language),
+	)
+
+	if err := validationChain(ctx, lr.Message, target); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	settings, err := uc.settings.GetSettings(ctx, lr.ChatID)
+	if err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	settings.Language = language
+	if err := uc.settings.SaveSettings(ctx, settings); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	return language, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/domain/validation"
+	"post-analyzer/internal/infrastructure/repository"
+)
+
+const (
+	maxCommentedPosts = 10
+	maxComments       = 20
+)
+
+// SetReadOptions applies the options to every subscription of the chat to
+// the channel and returns the resulting options.
+func (uc useCaseManager) SetReadOptions(ctx context.Context, or *dto.OptionsRequest) (string, error) {
+
+	target := &entity.Subscription{
+		ChatID: or.ChatID,
+	}
+	var options []string
+
+	validationChain := validation.OptionsArgsValidator(
+		validation.ReadOptionsValidator(
+			validation.ChannelNameValidator(
+				validation.InviteValidator(nil, uc.tgc),
+			),
+			&options,
+		),
+	)
+
+	if err := validationChain(ctx, or.Message, target); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, or.ChatID)
+	if err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	var updated *entity.Subscription
+	for _, sub := range subs {
+
+		if !sameChannel(sub, target) {
+			continue
+		}
+
+		for _, option := range options {
+			if err := sub.ReadOptions.Set(option); err != nil {
+				return "", presenter.PresentError(err)
+			}
+		}
+
+		if err := uc.repo.UpdateReadOptions(ctx, sub); err != nil {
+			return "", presenter.PresentError(err)
+		}
+
+		updated = sub
+	}
+
+	if updated == nil {
+		return "", presenter.PresentError(repository.ErrSubscriptionNotFound)
+	}
+
+	return updated.ReadOptions.String(), nil
+}
+
+// loadComments attaches the linked discussion comments to the most notable
+// posts when the subscription opts in. Comments are best effort, the digest
+// goes without them when they cannot be fetched.
+func (uc useCaseManager) loadComments(ctx context.Context, sub *entity.Subscription, posts []*entity.Post) {
+
+	if !sub.IncludeComments || len(posts) == 0 {
+		return
+	}
+
+	channel, err := uc.src.ChannelDetails(ctx, sub.ChannelID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if channel.LinkedChatID == 0 {
+		return
+	}
+
+	commented := posts[:min(maxCommentedPosts, len(posts))]
+	ids := make([]int64, 0, len(commented))
+	for _, post := range commented {
+		ids = append(ids, post.ID)
+	}
+
+	comments, err := uc.src.Comments(ctx, sub.ChannelID, ids, maxComments)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, post := range commented {
+		for _, comment := range comments[post.ID] {
+			if !sub.ExcludeBots || !comment.FromBot {
+				post.Comments = append(post.Comments, comment)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"post-analyzer/internal/adapters/feed"
+	"post-analyzer/internal/adapters/telegram/user"
+	"post-analyzer/internal/domain/dto"
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+	"post-analyzer/internal/domain/validation"
+	"post-analyzer/internal/infrastructure/repository"
+)
+
+func (uc useCaseManager) Pause(ctx context.Context, pr *dto.PauseRequest) error {
+
+	subs, err := uc.selectSubscriptions(ctx, pr.ChatID, pr.Message, true)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	for _, sub := range subs {
+
+		// the job is removed only after the flag is stored, so a failed
+		// update leaves the subscription fully working
+		previousID := sub.ScheduleID
+		sub.Active = false
+		sub.ScheduleID = 0
+
+		if err := uc.repo.UpdateSubscription(ctx, sub); err != nil {
+			return presenter.PresentError(err)
+		}
+		uc.sched.RemoveEvent(previousID)
+	}
+
+	return nil
+}
+
+// CheckPaused returns the ID of a paused subscription to the channel named
+// in the command, or 0 when the command names none. The channel reference
+// itself (invite links, feed URLs) may not fit into Telegram callback data.
+func (uc useCaseManager) CheckPaused(ctx context.Context, rr *dto.ResumeRequest) (int64, error) {
+
+	subs, err := uc.selectSubscriptions(ctx, rr.ChatID, rr.Message, false)
+	if err != nil {
+		return 0, presenter.PresentError(err)
+	}
+
+	if strings.TrimSpace(rr.Message) == "" {
+		return 0, nil
+	}
+
+	return subs[0].ID, nil
+}
+
+// Resume resumes the paused subscriptions to the channel of the given
+// subscription, or all paused subscriptions of the chat.
+func (uc useCaseManager) Resume(ctx context.Context, rr *dto.ResumeRequest) error {
+
+	subs, err := uc.selectSubscriptions(ctx, rr.ChatID, "", false)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	if rr.SubscriptionID != 0 {
+		if subs = sameChannelAs(subs, rr.SubscriptionID); len(subs) == 0 {
+			return presenter.PresentError(repository.ErrSubscriptionNotFound)
+		}
+	}
+
+	latestPosts := make(map[int64]int64)
+
+	for _, sub := range subs {
+
+		if !rr.CatchUp {
+
+			latest, ok := latestPosts[sub.ChannelID]
+			if !ok {
+				if latest, err = uc.latestPostID(ctx, sub); err != nil {
+					return presenter.PresentError(err)
+				}
+				latestPosts[sub.ChannelID] = latest
+			}
+
+			sub.LastCheckedPostID = latest
+		}
+
+		sub.Active = true
+		if err := uc.reschedule(ctx, sub); err != nil {
+			return presenter.PresentError(err)
+		}
+	}
+
+	return nil
+}
+
+// selectSubscriptions returns the chat's subscriptions in the requested state,
+// narrowed down to one channel when the command names it.
+func (uc useCaseManager) selectSubscriptions(ctx context.Context, chatID int64, command string, active bool) ([]*entity.Subscription, error) {
+
+	target := &entity.Subscription{
+		ChatID: chatID,
+	}
+
+	validationChain := validation.OptionalChannelValidator(
+		validation.ChannelNameValidator(
+			validation.InviteValidator(nil, uc.tgc),
+		),
+	)
+
+	if err := validationChain(ctx, command, target); err != nil {
+		return nil, err
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*entity.Subscription
+	for _, sub := range subs {
+
+		if sub.Active != active {
+			continue
+		}
+		if strings.TrimSpace(command) != "" && !sameChannel(sub, target) {
+			continue
+		}
+
+		selected = append(selected, sub)
+	}
+
+	if len(selected) == 0 {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+
+	return selected, nil
+}
+
+// sameChannelAs keeps the subscriptions to the channel of the subscription
+// with the ID, nothing when it is not among them.
+func sameChannelAs(subs []*entity.Subscription, id int64) []*entity.Subscription {
+
+	var channelID int64
+	for _, sub := range subs {
+		if sub.ID == id {
+			channelID = sub.ChannelID
+			break
+		}
+	}
+
+	var selected []*entity.Subscription
+	for _, sub := range subs {
+		if channelID != 0 && sub.ChannelID == channelID {
+			selected = append(selected, sub)
+		}
+	}
+
+	return selected
+}
+
+func (uc useCaseManager) latestPostID(ctx context.Context, sub *entity.Subscription) (int64, error) {
+
+	latest, err := uc.src.LatestPostID(ctx, sub.ChannelID)
+	if errors.Is(err, user.ErrNoPosts) || errors.Is(err, feed.ErrNoPosts) {
+		return sub.LastCheckedPostID, nil
+	}
+
+	return latest, err
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"post-analyzer/internal/domain/entity"
+	"post-analyzer/internal/domain/presenter"
+)
+
+const (
+	maxAnalyzedPosts = 100
+	topPostsShown    = 3
+
+	// forwards and reactions take an effort from the reader, views do not
+	viewsWeight     = 1.0
+	forwardsWeight  = 3.0
+	reactionsWeight = 2.0
+)
+
+type rankedPost struct {
+	post     *entity.Post
+	username string
+	label    string
+	score    float64
+}
+
+// rankPosts scores posts of one channel against the channel's median
+// engagement in the same batch, so small and large channels rank fairly,
+// and orders them from the most to the least notable.
+func rankPosts(posts []*entity.Post, username, label string) []rankedPost {
+
+	views := median(posts, func(p *entity.Post) int { return p.Views })
+	forwards := median(posts, func(p *entity.Post) int { return p.Forwards })
+	reactions := median(posts, func(p *entity.Post) int { return p.Reactions })
+
+	ranked := make([]rankedPost, 0, len(posts))
+	for _, post := range posts {
+		ranked = append(ranked, rankedPost{
+			post:     post,
+			username: username,
+			label:    label,
+			score: viewsWeight*relative(post.Views, views) +
+				forwardsWeight*relative(post.Forwards, forwards) +
+				reactionsWeight*relative(post.Reactions, reactions),
+		})
+	}
+
+	sortRanked(ranked)
+	return ranked
+}
+
+func sortRanked(ranked []rankedPost) {
+	slices.SortStableFunc(ranked, func(a, b rankedPost) int {
+		return cmp.Compare(b.score, a.score)
+	})
+}
+
+func median(posts []*entity.Post, value func(*entity.Post) int) int {
+
+	if len(posts) == 0 {
+		return 0
+	}
+
+	values := make([]int, 0, len(posts))
+	for _, post := range posts {
+		values = append(values, value(post))
+	}
+	slices.Sort(values)
+
+	return values[len(values)/2]
+}
+
+// relative is smoothed, so signals a channel barely gets (forwards of a
+// small channel) do not blow up the score.
+func relative(value, baseline int) float64 {
+	return float64(value+1) / float64(baseline+1)
+}
+
+// engagementFooter lists the top posts with their engagement and links.
+func engagementFooter(ranked []rankedPost, language entity.Language) string {
+
+	if len(ranked) < 2 {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, r := range ranked[:min(topPostsShown, len(ranked))] {
+
+		if r.post.Views == 0 && r.post.Forwards == 0 && r.post.Reactions == 0 {
+			continue
+		}
+
+		if builder.Len() == 0 {
+			builder.WriteString(presenter.Text(language, presenter.MsgEngagementHeader))
+		}
+
+		builder.WriteString(presenter.Text(language, presenter.MsgEngagementItem,
+			r.label, r.post.Excerpt(excerptLength),
+			presenter.Plural(language, r.post.Views, presenter.PluralViews),
+			presenter.Plural(language, r.post.Forwards, presenter.PluralForwards),
+			presenter.Plural(language, r.post.Reactions, presenter.PluralReactions),
+			r.post.URL(r.username)))
+	}
+
+	return builder.String()
+}
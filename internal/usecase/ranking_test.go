@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"post-analyzer/internal/domain/entity"
+)
+
+func TestMedian(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		views []int
+		want  int
+	}{
+		{"empty", nil, 0},
+		{"single", []int{7}, 7},
+		{"odd", []int{5, 1, 3}, 3},
+		{"even takes the upper middle", []int{4, 1, 3, 2}, 3},
+		{"outlier", []int{10, 12, 11, 100000}, 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			posts := make([]*entity.Post, 0, len(tt.views))
+			for _, views := range tt.views {
+				posts = append(posts, &entity.Post{Views: views})
+			}
+
+			if got := median(posts, func(p *entity.Post) int { return p.Views }); got != tt.want {
+				t.Errorf("median() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelative(t *testing.T) {
+
+	tests := []struct {
+		value, baseline int
+		want            float64
+	}{
+		{0, 0, 1},
+		{10, 10, 1},
+		{21, 10, 2},
+		{1, 0, 2},
+		{0, 9, 0.1},
+	}
+
+	for _, tt := range tests {
+		if got := relative(tt.value, tt.baseline); got != tt.want {
+			t.Errorf("relative(%d, %d) = %v, want %v", tt.value, tt.baseline, got, tt.want)
+		}
+	}
+}
+
+func TestRankPosts(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		posts []*entity.Post
+		want  []int64
+	}{
+		{
+			name: "forwards outweigh views",
+			posts: []*entity.Post{
+				{ID: 1, Views: 200, Forwards: 1},
+				{ID: 2, Views: 100, Forwards: 10},
+				{ID: 3, Views: 100, Forwards: 1},
+			},
+			want: []int64{2, 1, 3},
+		},
+		{
+			name: "reactions count",
+			posts: []*entity.Post{
+				{ID: 1, Views: 100},
+				{ID: 2, Views: 100, Reactions: 20},
+				{ID: 3, Views: 100, Reactions: 1},
+			},
+			want: []int64{2, 3, 1},
+		},
+		{
+			name: "ties keep the newest first",
+			posts: []*entity.Post{
+				{ID: 3, Views: 50},
+				{ID: 2, Views: 50},
+				{ID: 1, Views: 50},
+			},
+			want: []int64{3, 2, 1},
+		},
+		{
+			name:  "empty",
+			posts: nil,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ranked := rankPosts(tt.posts, "channel", "@channel")
+			if len(ranked) != len(tt.want) {
+				t.Fatalf("got %d ranked posts, want %d", len(ranked), len(tt.want))
+			}
+			for i, r := range ranked {
+				if r.post.ID != tt.want[i] {
+					t.Errorf("position %d: post %d, want %d", i, r.post.ID, tt.want[i])
+				}
+				if r.label != "@channel" || r.username != "channel" {
+					t.Errorf("position %d: source %q %q, want the channel", i, r.username, r.label)
+				}
+			}
+		})
+	}
+}
+
+// Channels are scored against their own median, so a small channel's
+// standout post ranks above a large channel's ordinary one.
+func TestRankPostsAcrossChannels(t *testing.T) {
+
+	small := rankPosts([]*entity.Post{
+		{ID: 1, Views: 100, Forwards: 30},
+		{ID: 2, Views: 100, Forwards: 1},
+		{ID: 3, Views: 100, Forwards: 1},
+	}, "small", "@small")
+
+	large := rankPosts([]*entity.Post{
+		{ID: 1, Views: 100000, Forwards: 500},
+		{ID: 2, Views: 100000, Forwards: 500},
+		{ID: 3, Views: 100000, Forwards: 500},
+	}, "large", "@large")
+
+	ranked := append(small, large...)
+	sortRanked(ranked)
+
+	if ranked[0].username != "small" || ranked[0].post.ID != 1 {
+		t.Errorf("top post is %s/%d, want small/1", ranked[0].username, ranked[0].post.ID)
+	}
+}
+
+func TestEngagementFooter(t *testing.T) {
+
+	posts := []*entity.Post{
+		{ID: 1, Text: "first", Views: 100, Forwards: 2, Reactions: 1},
+		{ID: 2, Text: "second", Views: 10},
+		{ID: 3, Text: "quiet"},
+	}
+
+	footer := engagementFooter(rankPosts(posts, "channel", "@channel"), entity.LanguageEnglish)
+
+	for _, want := range []string{"first", "100 views", "2 reposts", "1 reaction", "https://t.me/channel/1", "second"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("footer %q does not contain %q", footer, want)
+		}
+	}
+	if strings.Contains(footer, "quiet") {
+		t.Errorf("footer %q lists a post without engagement", footer)
+	}
+
+	if footer := engagementFooter(rankPosts(posts[:1], "channel", "@channel"), entity.LanguageEnglish); footer != "" {
+		t.Errorf("footer for a single post = %q, want none", footer)
+	}
+}
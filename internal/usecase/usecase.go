@@ -2,11 +2,15 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"post-analyzer/internal/adapters/feed"
 	"post-analyzer/internal/adapters/openrouter"
+	"post-analyzer/internal/adapters/source"
 	"post-analyzer/internal/adapters/telegram/user"
 	"post-analyzer/internal/domain/dto"
 	"post-analyzer/internal/domain/entity"
@@ -19,25 +23,69 @@ import (
 
 type UseCase interface {
 	MonitorChannel(ctx context.Context, mr *dto.MonitorRequest) error
+	CheckChannel(ctx context.Context, cr *dto.ChannelRequest) (string, error)
+	RestoreSchedules(ctx context.Context) error
+	Digest(ctx context.Context, dr *dto.DigestRequest) error
+	Unsubscribe(ctx context.Context, ur *dto.UnsubscribeRequest) error
+	EditSubscription(ctx context.Context, er *dto.EditRequest) error
+	ListSubscriptions(ctx context.Context, lr *dto.ListRequest) (*dto.SubscriptionsPage, error)
+	SetTimezone(ctx context.Context, tr *dto.TimezoneRequest) error
+	ChatLanguage(ctx context.Context, lr *dto.LanguageRequest) entity.Language
+	SetLanguage(ctx context.Context, lr *dto.LanguageRequest) (entity.Language, error)
+	Pause(ctx context.Context, pr *dto.PauseRequest) error
+	CheckPaused(ctx context.Context, rr *dto.ResumeRequest) (int64, error)
+	Resume(ctx context.Context, rr *dto.ResumeRequest) error
+	SetReadOptions(ctx context.Context, or *dto.OptionsRequest) (string, error)
+	SetModelOptions(ctx context.Context, mr *dto.ModelRequest) (string, error)
+	SetPrompt(ctx context.Context, pr *dto.PromptRequest) (string, error)
+	CreateBundle(ctx context.Context, br *dto.BundleRequest) error
+	DeleteBundle(ctx context.Context, br *dto.BundleRequest) error
+	ListBundles(ctx context.Context, br *dto.BundleRequest) ([]dto.BundleInfo, error)
 }
 
+const (
+	subscriptionsPageSize = 5
+	defaultDigestHours    = 24
+	digestTimeout         = 2 * time.Minute
+
+	channelDigestHeader = "%s\n%s\n\n"
+)
+
 type useCaseManager struct {
 	tgc      user.TelegramService
+	feeds    feed.FeedService
+	src      source.Source
 	repo     repository.SubscriptionRepository
+	settings repository.SettingsRepository
+	bundles  repository.BundleRepository
+	sent     repository.SentPostRepository
 	sched    scheduler.Scheduler
 	ai       openrouter.AnalysisService
 	notifier notifier.Notifier
+
+	// models chats may choose for their digests
+	models []string
 }
 
-func NewUseCaseManager(tgc user.TelegramService, repo repository.SubscriptionRepository, sched scheduler.Scheduler,
-	ai openrouter.AnalysisService, notifier notifier.Notifier) *useCaseManager {
+// NewUseCaseManager reads Telegram channels through tgc and web feeds
+// through feeds, digests treat both alike. Chats may switch their digests
+// only to the given models, which all run on the bot's account.
+func NewUseCaseManager(tgc user.TelegramService, feeds feed.FeedService, repo repository.SubscriptionRepository, settings repository.SettingsRepository,
+	bundles repository.BundleRepository, sent repository.SentPostRepository, sched scheduler.Scheduler, ai openrouter.AnalysisService,
+	notifier notifier.Notifier, models []string) *useCaseManager {
 
 	return &useCaseManager{
 		tgc:      tgc,
+		feeds:    feeds,
+		src:      source.NewRouter(tgc, feeds),
 		repo:     repo,
+		settings: settings,
+		bundles:  bundles,
+		sent:     sent,
 		sched:    sched,
 		ai:       ai,
 		notifier: notifier,
+		models:   models,
 	}
 }
 
@@ -46,12 +94,13 @@ func (uc useCaseManager) MonitorChannel(ctx context.Context, mr *dto.MonitorRequ
 	subscription := &entity.Subscription{
 		ChatID:            mr.ChatID,
 		LastCheckedPostID: -1,
+		Active:            true,
 	}
 
 	validationChain := validation.ArgsValidator(
-		validation.TimeValidator(
+		validation.ScheduleValidator(
 			validation.ChannelNameValidator(
-				validation.ChannelValidator(nil, uc.tgc),
+				validation.ChannelValidator(nil, uc.tgc, uc.feeds),
 			),
 		),
 	)
@@ -60,52 +109,592 @@ func (uc useCaseManager) MonitorChannel(ctx context.Context, mr *dto.MonitorRequ
 		return presenter.PresentError(err)
 	}
 
+	settings, err := uc.settings.GetSettings(ctx, mr.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+	subscription.Timezone = settings.Timezone
+
 	if err := uc.repo.AddSubscription(ctx, subscription); err != nil {
 		return presenter.PresentError(err)
 	}
 
-	var err error
-	if subscription.ScheduleID, err = uc.sched.ScheduleEvent(subscription,
-		func() {
+	if subscription.ScheduleID, err = uc.sched.ScheduleEvent(subscription.Schedule, subscription.Timezone, uc.digestJob(subscription.ID)); err != nil {
+		return presenter.PresentError(err)
+	}
 
-			analysisCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
+	if err := uc.repo.UpdateSubscription(ctx, subscription); err != nil {
+		return presenter.PresentError(err)
+	}
 
-			posts, err := uc.tgc.ChannelPosts(analysisCtx, subscription.ChannelUsername, subscription.LastCheckedPostID)
-			if err != nil {
-				log.Println(err)
-				return
-			}
+	uc.src.Watch(subscription.ChannelID)
 
-			subscription.LastCheckedPostID = int64(posts[0].ID)
-			if err := uc.repo.UpdateSubscription(analysisCtx, subscription); err != nil {
-				log.Println(err)
-				return
-			}
+	return nil
+}
 
-			var postsBuilder strings.Builder
-			for _, post := range posts {
-				postsBuilder.WriteString(post.Message + "\n")
-			}
-			postTexts := postsBuilder.String()
+func (uc useCaseManager) CheckChannel(ctx context.Context, cr *dto.ChannelRequest) (string, error) {
 
-			result, err := uc.ai.AnalyzePosts(analysisCtx, postTexts)
-			if err != nil {
-				log.Println(err)
-				return
-			}
+	target := &entity.Subscription{
+		ChatID: cr.ChatID,
+	}
 
-			if err := uc.notifier.NotifyWithText(analysisCtx, subscription.ChatID, result); err != nil {
-				log.Println(err)
-			}
+	validationChain := validation.SingleArgValidator(
+		validation.ChannelNameValidator(
+			validation.ChannelCheckValidator(nil, uc.tgc, uc.feeds),
+		),
+	)
+
+	if err := validationChain(ctx, cr.Message, target); err != nil {
+		return "", presenter.PresentError(err)
+	}
+
+	return entity.ChannelLabel(target.ChannelUsername, target.ChannelTitle), nil
+}
+
+func (uc useCaseManager) RestoreSchedules(ctx context.Context) error {
 
-		}); err != nil {
+	subs, err := uc.repo.GetAllSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+
+		// paused subscriptions keep ingesting, so resuming can catch up
+		uc.src.Watch(sub.ChannelID)
+
+		if !sub.Active {
+			continue
+		}
+
+		if sub.ScheduleID, err = uc.sched.ScheduleEvent(sub.Schedule, sub.Timezone, uc.digestJob(sub.ID)); err != nil {
+			// the stored ID belongs to the previous run, keep none
+			log.Printf("RestoreSchedules: channel %d for chat %d: %v", sub.ChannelID, sub.ChatID, err)
+			sub.ScheduleID = 0
+		}
+
+		if err := uc.repo.UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+	}
+
+	bundles, err := uc.bundles.GetAllBundles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bundle := range bundles {
+
+		for _, channel := range bundle.Channels {
+			uc.src.Watch(channel.ChannelID)
+		}
+
+		if bundle.ScheduleID, err = uc.sched.ScheduleEvent(bundle.Schedule, bundle.Timezone, uc.bundleJob(bundle)); err != nil {
+			log.Printf("RestoreSchedules: bundle %q for chat %d: %v", bundle.Name, bundle.ChatID, err)
+			bundle.ScheduleID = 0
+		}
+
+		if err := uc.bundles.UpdateBundle(ctx, bundle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc useCaseManager) Digest(ctx context.Context, dr *dto.DigestRequest) error {
+
+	target := &entity.Subscription{
+		ChatID: dr.ChatID,
+	}
+	hours := defaultDigestHours
+
+	validationChain := validation.OptionalArgsValidator(
+		validation.HoursValidator(
+			validation.ChannelNameValidator(
+				validation.ChannelValidator(nil, uc.tgc, uc.feeds),
+			),
+			&hours,
+		),
+	)
+
+	if err := validationChain(ctx, dr.Message, target); err != nil {
 		return presenter.PresentError(err)
 	}
 
-	if err := uc.repo.UpdateSubscription(ctx, subscription); err != nil {
+	digestCtx, cancel := context.WithTimeout(ctx, digestTimeout)
+	defer cancel()
+
+	settings := uc.chatSettings(digestCtx, target.ChatID)
+
+	// edits and deletions are reported only for channels the chat is
+	// subscribed to, a one-off digest of another channel is not followed up
+	sub, subscribed := uc.subscribedChannels(digestCtx, target.ChatID)[target.ChannelID]
+	if subscribed {
+		target.ReadOptions = sub.ReadOptions
+
+		label := entity.ChannelLabel(target.ChannelUsername, target.ChannelTitle)
+		if notes, err := uc.corrections(digestCtx, target.ChatID, target.ChannelID, label, settings.OutputLanguage()); err != nil {
+			log.Println(err)
+		} else {
+			uc.sendCorrections(digestCtx, target.ChatID, settings.OutputLanguage(), notes)
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	history, err := uc.src.ChannelRecentPosts(digestCtx, target.ChannelID, since)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	if history.Posts = target.Filter(history.Posts); len(history.Posts) == 0 {
+		return presenter.PresentError(user.ErrNoPosts)
+	}
+
+	sent, err := uc.sendDigest(digestCtx, settings, target, history)
+	if err != nil {
 		return presenter.PresentError(err)
 	}
+	if subscribed {
+		uc.rememberSent(digestCtx, target.ChatID, target.ChannelID, target.ChannelUsername, sent)
+	}
+
+	return nil
+}
+
+func (uc useCaseManager) Unsubscribe(ctx context.Context, ur *dto.UnsubscribeRequest) error {
+
+	target := &entity.Subscription{
+		ChatID: ur.ChatID,
+	}
+
+	validationChain := validation.ChannelArgsValidator(
+		validation.OptionalScheduleValidator(
+			validation.ChannelNameValidator(
+				validation.InviteValidator(nil, uc.tgc),
+			),
+		),
+	)
+
+	if err := validationChain(ctx, ur.Message, target); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, ur.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	var removed []int64
+	for _, sub := range subs {
+
+		if !sameChannel(sub, target) {
+			continue
+		}
+		if !target.Schedule.IsZero() && sub.Schedule.String() != target.Schedule.String() {
+			continue
+		}
+
+		// the job is removed only after the row is gone, so a failed
+		// deletion leaves the subscription fully working
+		if err := uc.repo.DeleteSubscription(ctx, sub); err != nil {
+			return presenter.PresentError(err)
+		}
+		uc.sched.RemoveEvent(sub.ScheduleID)
+
+		removed = append(removed, sub.ChannelID)
+	}
+
+	if len(removed) == 0 {
+		return presenter.PresentError(repository.ErrSubscriptionNotFound)
+	}
+
+	uc.unwatchUnfollowed(ctx, removed)
 
 	return nil
 }
+
+// unwatchUnfollowed stops real-time ingestion of the channels that no
+// subscription or bundle reads anymore.
+func (uc useCaseManager) unwatchUnfollowed(ctx context.Context, channelIDs []int64) {
+
+	for _, channelID := range channelIDs {
+
+		followed, err := uc.repo.ChannelFollowed(ctx, channelID)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if !followed {
+			uc.src.Unwatch(channelID)
+		}
+	}
+}
+
+func (uc useCaseManager) EditSubscription(ctx context.Context, er *dto.EditRequest) error {
+
+	target := &entity.Subscription{
+		ChatID: er.ChatID,
+	}
+	var newSchedule entity.Schedule
+
+	validationChain := validation.EditArgsValidator(
+		validation.EditScheduleValidator(
+			validation.ChannelNameValidator(
+				validation.InviteValidator(nil, uc.tgc),
+			),
+			&newSchedule,
+		),
+	)
+
+	if err := validationChain(ctx, er.Message, target); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, er.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	var current *entity.Subscription
+	for _, sub := range subs {
+		if sameChannel(sub, target) && sub.Schedule.String() == target.Schedule.String() {
+			current = sub
+			break
+		}
+	}
+
+	if current == nil {
+		return presenter.PresentError(repository.ErrSubscriptionNotFound)
+	}
+
+	if current.Schedule.String() == newSchedule.String() {
+		return nil
+	}
+
+	// the loaded row keeps last_checked_id, so the new job continues
+	// exactly where the old one stopped
+	updated := *current
+	updated.Schedule = newSchedule
+
+	// paused subscriptions only get the new schedule stored
+	if updated.Active {
+		if updated.ScheduleID, err = uc.sched.ScheduleEvent(updated.Schedule, updated.Timezone, uc.digestJob(updated.ID)); err != nil {
+			return presenter.PresentError(err)
+		}
+	}
+
+	if err := uc.repo.RescheduleSubscription(ctx, &updated, current.Schedule); err != nil {
+		uc.sched.RemoveEvent(updated.ScheduleID)
+		return presenter.PresentError(err)
+	}
+	uc.sched.RemoveEvent(current.ScheduleID)
+
+	return nil
+}
+
+func (uc useCaseManager) ListSubscriptions(ctx context.Context, lr *dto.ListRequest) (*dto.SubscriptionsPage, error) {
+
+	subs, err := uc.repo.GetSubscriptions(ctx, lr.ChatID)
+	if err != nil {
+		return nil, presenter.PresentError(err)
+	}
+
+	totalPages := max(1, (len(subs)+subscriptionsPageSize-1)/subscriptionsPageSize)
+	page := min(max(lr.Page, 0), totalPages-1)
+
+	start := page * subscriptionsPageSize
+	end := min(start+subscriptionsPageSize, len(subs))
+
+	location, err := uc.chatLocation(ctx, lr.ChatID)
+	if err != nil {
+		return nil, presenter.PresentError(err)
+	}
+
+	infos := make([]dto.SubscriptionInfo, 0, end-start)
+	for _, sub := range subs[start:end] {
+		infos = append(infos, dto.SubscriptionInfo{
+			Channel:           entity.ChannelName(sub.ChannelUsername, sub.ChannelTitle),
+			Schedule:          sub.Schedule.String(),
+			LastCheckedPostID: sub.LastCheckedPostID,
+			Active:            sub.Active,
+			NextRun:           uc.sched.NextRun(sub.ScheduleID).In(location),
+		})
+	}
+
+	return &dto.SubscriptionsPage{
+		Subscriptions: infos,
+		Timezone:      location.String(),
+		Page:          page,
+		TotalPages:    totalPages,
+	}, nil
+}
+
+func (uc useCaseManager) SetTimezone(ctx context.Context, tr *dto.TimezoneRequest) error {
+
+	target := &entity.Subscription{
+		ChatID: tr.ChatID,
+	}
+
+	validationChain := validation.SingleArgValidator(
+		validation.TimezoneValidator(nil),
+	)
+
+	if err := validationChain(ctx, tr.Message, target); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	settings, err := uc.settings.GetSettings(ctx, tr.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	settings.Timezone = target.Timezone
+	if err := uc.settings.SaveSettings(ctx, settings); err != nil {
+		return presenter.PresentError(err)
+	}
+
+	subs, err := uc.repo.GetSubscriptions(ctx, tr.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	for _, sub := range subs {
+
+		if !sub.Active {
+			continue
+		}
+
+		if err := uc.reschedule(ctx, sub); err != nil {
+			return presenter.PresentError(err)
+		}
+	}
+
+	bundles, err := uc.bundles.GetBundles(ctx, tr.ChatID)
+	if err != nil {
+		return presenter.PresentError(err)
+	}
+
+	for _, bundle := range bundles {
+		if err := uc.rescheduleBundle(ctx, bundle); err != nil {
+			return presenter.PresentError(err)
+		}
+	}
+
+	return nil
+}
+
+func (uc useCaseManager) chatLocation(ctx context.Context, chatID int64) (*time.Location, error) {
+
+	settings, err := uc.settings.GetSettings(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Timezone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(settings.Timezone)
+}
+
+func (uc useCaseManager) reschedule(ctx context.Context, sub *entity.Subscription) error {
+
+	previousID := sub.ScheduleID
+
+	var err error
+	if sub.ScheduleID, err = uc.sched.ScheduleEvent(sub.Schedule, sub.Timezone, uc.digestJob(sub.ID)); err != nil {
+		return err
+	}
+
+	if err := uc.repo.UpdateSubscription(ctx, sub); err != nil {
+		uc.sched.RemoveEvent(sub.ScheduleID)
+		return err
+	}
+	uc.sched.RemoveEvent(previousID)
+
+	return nil
+}
+
+// digestJob reads the subscription when it runs, so changed options and
+// the stored read position apply without rescheduling the job.
+func (uc useCaseManager) digestJob(subscriptionID int64) func() {
+
+	var job func()
+	job = func() {
+
+		analysisCtx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+		defer cancel()
+
+		subscription, err := uc.repo.GetSubscription(analysisCtx, subscriptionID)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		settings := uc.chatSettings(analysisCtx, subscription.ChatID)
+
+		label := entity.ChannelLabel(subscription.ChannelUsername, subscription.ChannelTitle)
+		if notes, err := uc.corrections(analysisCtx, subscription.ChatID, subscription.ChannelID, label, settings.OutputLanguage()); err != nil {
+			log.Println(err)
+		} else {
+			uc.sendCorrections(analysisCtx, subscription.ChatID, settings.OutputLanguage(), notes)
+		}
+
+		history, err := uc.src.ChannelPosts(analysisCtx, subscription.ChannelID, subscription.LastCheckedPostID)
+		if err != nil {
+			if !uc.delayOnFloodWait(err, subscription.ScheduleID, job) {
+				log.Println(err)
+			}
+			return
+		}
+
+		if len(history.Posts) == 0 {
+			return
+		}
+
+		subscription.LastCheckedPostID = history.NextReadID()
+		if err := uc.repo.UpdateLastChecked(analysisCtx, subscription); err != nil {
+			log.Println(err)
+			return
+		}
+
+		// filtered out messages still count as read
+		if history.Posts = subscription.Filter(history.Posts); len(history.Posts) == 0 {
+			return
+		}
+
+		sent, err := uc.sendDigest(analysisCtx, settings, subscription, history)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		uc.rememberSent(analysisCtx, subscription.ChatID, subscription.ChannelID, subscription.ChannelUsername, sent)
+	}
+
+	return job
+}
+
+// sameChannel matches by ID when the command resolved one (invite links)
+// and by username otherwise, which saves a Telegram call for public channels.
+func sameChannel(sub, target *entity.Subscription) bool {
+
+	if target.ChannelID != 0 {
+		return sub.ChannelID == target.ChannelID
+	}
+
+	return target.ChannelUsername != "" && strings.EqualFold(sub.ChannelUsername, target.ChannelUsername)
+}
+
+// delayOnFloodWait reruns the job once Telegram lifts the flood wait,
+// so the digest comes late instead of being dropped until the next run.
+// The rerun belongs to the scheduled event and is cancelled with it.
+func (uc useCaseManager) delayOnFloodWait(err error, scheduleID int, job func()) bool {
+
+	var floodErr *user.FloodWaitError
+	if !errors.As(err, &floodErr) {
+		return false
+	}
+
+	log.Printf("%v, digest delayed", err)
+	uc.sched.Delay(scheduleID, floodErr.Wait, job)
+
+	return true
+}
+
+// sendDigest passes the most notable posts to the model, from the most to
+// the least notable, and returns the posts the digest was built from.
+func (uc useCaseManager) sendDigest(ctx context.Context, settings *entity.ChatSettings, sub *entity.Subscription,
+	history *entity.ChannelHistory) ([]*entity.Post, error) {
+
+	language := settings.OutputLanguage()
+
+	ranked := rankPosts(history.Posts, sub.ChannelUsername, entity.ChannelLabel(sub.ChannelUsername, sub.ChannelTitle))
+	analyzed := ranked[:min(maxAnalyzedPosts, len(ranked))]
+
+	posts := make([]*entity.Post, 0, len(analyzed))
+	for _, r := range analyzed {
+		posts = append(posts, r.post)
+	}
+	uc.loadComments(ctx, sub, posts)
+
+	var postsBuilder strings.Builder
+	for _, post := range posts {
+		postsBuilder.WriteString(post.Content() + "\n")
+	}
+	postTexts := postsBuilder.String()
+
+	header := uc.channelHeader(ctx, sub.ChannelID, len(history.Posts), language)
+	if history.Limited {
+		log.Printf("digest for chat %d: fetched %d messages, truncated %d", sub.ChatID, history.Fetched, history.Truncated)
+		header += truncatedHeader(language, len(history.Posts), history.Truncated)
+	}
+	if len(analyzed) < len(ranked) {
+		header += presenter.Text(language, presenter.MsgRankedHeader, len(ranked), len(analyzed))
+	}
+
+	if err := uc.analyzeAndNotify(ctx, settings, sub.Analysis, header, postTexts, engagementFooter(ranked, language)); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// channelHeader names the channel and its audience, the digest goes
+// without a header when the channel info is unavailable.
+func (uc useCaseManager) channelHeader(ctx context.Context, channelID int64, posts int, language entity.Language) string {
+
+	channel, err := uc.src.ChannelDetails(ctx, channelID)
+	if err != nil {
+		log.Println(err)
+		return ""
+	}
+
+	details := []string{presenter.Text(language, presenter.MsgDigestPosts, presenter.Plural(language, posts, presenter.PluralPosts))}
+	if channel.Participants > 0 {
+		details = append([]string{presenter.Plural(language, channel.Participants, presenter.PluralSubscribers)}, details...)
+	}
+
+	return fmt.Sprintf(channelDigestHeader, entity.ChannelName(channel.Username, channel.Title), strings.Join(details, " · "))
+}
+
+// truncatedHeader tells that the fetch limit left posts out, with their
+// number when the source could count them.
+func truncatedHeader(language entity.Language, posts, truncated int) string {
+
+	if truncated > 0 {
+		return presenter.Text(language, presenter.MsgTruncatedHeader, posts, truncated)
+	}
+	return presenter.Text(language, presenter.MsgLimitedHeader, posts)
+}
+
+// chatSettings reads the chat settings on every run, so changing them needs
+// no rescheduling. A failed read falls back to the defaults instead of
+// dropping the digest.
+func (uc useCaseManager) chatSettings(ctx context.Context, chatID int64) *entity.ChatSettings {
+
+	settings, err := uc.settings.GetSettings(ctx, chatID)
+	if err != nil {
+		log.Println(err)
+		return &entity.ChatSettings{ChatID: chatID}
+	}
+
+	return settings
+}
+
+// analyzeAndNotify fills the unset model settings from the chat defaults
+// and asks for the analysis in the chat language.
+func (uc useCaseManager) analyzeAndNotify(ctx context.Context, settings *entity.ChatSettings, options entity.AnalysisOptions, header string, postTexts string, footer string) error {
+
+	result, err := uc.ai.AnalyzePosts(ctx, &openrouter.AnalysisRequest{
+		Posts:    postTexts,
+		Options:  options.Or(settings.Analysis),
+		Language: settings.OutputLanguage(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.notifier.NotifyWithText(ctx, settings.ChatID, header+result+footer)
+}